@@ -1,6 +1,6 @@
 module github.com/szymongib/graphql-client
 
-go 1.13
+go 1.18
 
 require (
 	github.com/99designs/gqlgen v0.10.1
@@ -11,3 +11,15 @@ require (
 	github.com/vektah/gqlparser v1.1.2
 	github.com/vrischmann/envconfig v1.2.0
 )
+
+require (
+	github.com/agnivade/levenshtein v1.0.1 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/gorilla/context v0.0.0-20160226214623-1ea25387ff6f // indirect
+	github.com/gorilla/websocket v1.2.0 // indirect
+	github.com/hashicorp/golang-lru v0.5.0 // indirect
+	github.com/konsorten/go-windows-terminal-sequences v1.0.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.0.0-20190422165155-953cdadca894 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
@@ -0,0 +1,105 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync/atomic"
+)
+
+const (
+	persistedQueryExtension  = "persistedQuery"
+	persistedQueryHashKey    = "sha256Hash"
+	persistedQueryVersionKey = "version"
+	persistedQueryVersion    = 1
+
+	persistedQueryNotFound         = "PersistedQueryNotFound"
+	persistedQueryNotFoundCode     = "PERSISTED_QUERY_NOT_FOUND"
+	persistedQueryNotSupported     = "PersistedQueryNotSupported"
+	persistedQueryNotSupportedCode = "PERSISTED_QUERY_NOT_SUPPORTED"
+)
+
+// persistedQueries implements Automatic Persisted Queries protocol.
+// Requests are sent with the hash of the query only, and if the server does not know the hash
+// the request is sent again with the full query so that the server can store it.
+type persistedQueries struct {
+	// notSupported is set to 1 if the server responded that it does not support persisted queries
+	notSupported int32
+}
+
+func newPersistedQueries() *persistedQueries {
+	return &persistedQueries{}
+}
+
+func persistedQueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// withPersistedQueries wraps the handler to send requests using Automatic Persisted Queries
+func (c Client) withPersistedQueries(handler Handler) Handler {
+	if c.options.persistedQueries == nil {
+		return handler
+	}
+	persisted := c.options.persistedQueries
+
+	return func(ctx context.Context, request Request, responseOut interface{}) (*Response, error) {
+		if atomic.LoadInt32(&persisted.notSupported) == 1 || request.Query == "" {
+			return handler(ctx, request, responseOut)
+		}
+
+		fullRequest := request
+		fullRequest.Extensions = withPersistedQueryExtension(request.Extensions, persistedQueryHash(request.Query))
+
+		hashedRequest := fullRequest
+		hashedRequest.Query = ""
+		hashedRequest.operation = request.OperationType()
+
+		response, err := handler(ctx, hashedRequest, responseOut)
+		if err == nil {
+			return response, nil
+		}
+
+		switch {
+		case isPersistedQueryError(err, persistedQueryNotFound, persistedQueryNotFoundCode):
+			c.log(ctx, LogLevelDebug, "Persisted query not found, sending full query")
+			return handler(ctx, fullRequest, responseOut)
+		case isPersistedQueryError(err, persistedQueryNotSupported, persistedQueryNotSupportedCode):
+			c.log(ctx, LogLevelWarn, "Persisted queries are not supported by the server, disabling them")
+			atomic.StoreInt32(&persisted.notSupported, 1)
+			return handler(ctx, request, responseOut)
+		}
+
+		return response, err
+	}
+}
+
+func withPersistedQueryExtension(extensions map[string]interface{}, hash string) map[string]interface{} {
+	withPersistedQuery := make(map[string]interface{}, len(extensions)+1)
+	for k, v := range extensions {
+		withPersistedQuery[k] = v
+	}
+
+	withPersistedQuery[persistedQueryExtension] = map[string]interface{}{
+		persistedQueryVersionKey: persistedQueryVersion,
+		persistedQueryHashKey:    hash,
+	}
+
+	return withPersistedQuery
+}
+
+func isPersistedQueryError(err error, message, code string) bool {
+	var gqlErrs GraphQLErrors
+	if !errors.As(err, &gqlErrs) {
+		return false
+	}
+
+	for _, gqlErr := range gqlErrs {
+		if gqlErr.Message == message || gqlErr.Code() == code {
+			return true
+		}
+	}
+
+	return false
+}
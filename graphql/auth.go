@@ -0,0 +1,165 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	AuthorizationHeader = "Authorization"
+
+	unauthenticatedErrorCode = "UNAUTHENTICATED"
+
+	// tokenExpiryDelta is subtracted from the token expiry to not use tokens that are about to expire
+	tokenExpiryDelta = 10 * time.Second
+)
+
+// Token is the access token used to authenticate requests
+type Token struct {
+	AccessToken string
+	// TokenType is the type of the token used in Authorization header, if empty Bearer is used
+	TokenType string
+	// Expiry is the time when the token expires, zero value means that the token does not expire
+	Expiry time.Time
+}
+
+func (t *Token) valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	if t.Expiry.IsZero() {
+		return true
+	}
+
+	return time.Now().Add(tokenExpiryDelta).Before(t.Expiry)
+}
+
+func (t *Token) authorizationHeader() string {
+	tokenType := t.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+
+	return tokenType + " " + t.AccessToken
+}
+
+// TokenSource provides tokens used to authenticate requests
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenSourceFunc is an adapter allowing to use ordinary function as TokenSource
+type TokenSourceFunc func(ctx context.Context) (*Token, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (*Token, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource returns TokenSource always providing the same bearer token
+func StaticTokenSource(accessToken string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		return &Token{AccessToken: accessToken}, nil
+	})
+}
+
+// cachingTokenSource reuses the token until it expires or is invalidated
+type cachingTokenSource struct {
+	source TokenSource
+
+	mutex sync.Mutex
+	token *Token
+}
+
+func newCachingTokenSource(source TokenSource) *cachingTokenSource {
+	return &cachingTokenSource{source: source}
+}
+
+func (s *cachingTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.token.valid() {
+		return s.token, nil
+	}
+
+	token, err := s.source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || token.AccessToken == "" {
+		return nil, fmt.Errorf("token source returned empty token")
+	}
+	s.token = token
+
+	return token, nil
+}
+
+// invalidate removes the token from the cache unless it has already been replaced by a new one
+func (s *cachingTokenSource) invalidate(token *Token) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.token == token {
+		s.token = nil
+	}
+}
+
+// withAuth wraps the handler to authenticate requests with tokens from the token source.
+// If the server rejects the token, the token is refreshed and the request is retried once.
+func (c Client) withAuth(handler Handler) Handler {
+	if c.options.tokenSource == nil {
+		return handler
+	}
+	tokenSource := c.options.tokenSource
+
+	return func(ctx context.Context, request Request, responseOut interface{}) (*Response, error) {
+		token, err := authenticate(ctx, tokenSource, &request)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := handler(ctx, request, responseOut)
+		if err == nil || !isUnauthenticated(err) {
+			return response, err
+		}
+
+		c.log(ctx, LogLevelInfo, "Refreshing token after the request was rejected as unauthenticated")
+		tokenSource.invalidate(token)
+
+		if _, err := authenticate(ctx, tokenSource, &request); err != nil {
+			return response, err
+		}
+
+		return handler(ctx, request, responseOut)
+	}
+}
+
+func authenticate(ctx context.Context, tokenSource *cachingTokenSource, request *Request) (*Token, error) {
+	token, err := tokenSource.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authentication token: %w", err)
+	}
+
+	// Headers are copied to not modify headers passed by the caller
+	request.Header = request.Header.Clone()
+	if request.Header == nil {
+		request.Header = http.Header{}
+	}
+	request.Header.Set(AuthorizationHeader, token.authorizationHeader())
+
+	return token, nil
+}
+
+func isUnauthenticated(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+
+	var gqlErrs GraphQLErrors
+	return errors.As(err, &gqlErrs) && gqlErrs.HasCode(unauthenticatedErrorCode)
+}
@@ -0,0 +1,311 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type batchingOptions struct {
+	window  time.Duration
+	maxSize int
+}
+
+// batchedOperation is the operation waiting to be sent in the batch
+type batchedOperation struct {
+	ctx     context.Context
+	request Request
+	result  chan batchResult
+}
+
+// batchResult is the result of the batched operation.
+// Data is decoded by the caller, so that the response is not written after the caller stopped waiting for it.
+type batchResult struct {
+	response *Response
+	data     json.RawMessage
+	err      error
+	// unbatched is set if the operation was the only one in the batch and should be executed as a regular request
+	unbatched bool
+}
+
+type batch struct {
+	operations []*batchedOperation
+	timer      *time.Timer
+}
+
+// traceContextHeaders are headers propagating trace context, which are unique for each operation.
+// They are ignored when operations are collected into batches, the batch is sent with headers of its first operation.
+var traceContextHeaders = []string{"Traceparent", "Tracestate", "Baggage"}
+
+// batcher collects operations into batches, which are sent when the window passes or the batch is full.
+// Operations with different headers, other than trace context headers, are collected in separate batches.
+type batcher struct {
+	options batchingOptions
+	send    func(operations []*batchedOperation)
+
+	mutex   sync.Mutex
+	batches map[string]*batch
+}
+
+func (b *batcher) add(operation *batchedOperation) {
+	key := headersKey(operation.request.Header)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	pending, ok := b.batches[key]
+	if !ok {
+		pending = &batch{}
+		pending.timer = time.AfterFunc(b.options.window, func() {
+			b.flush(key, pending)
+		})
+		b.batches[key] = pending
+	}
+
+	pending.operations = append(pending.operations, operation)
+
+	if b.options.maxSize > 0 && len(pending.operations) >= b.options.maxSize {
+		pending.timer.Stop()
+		delete(b.batches, key)
+		go b.send(pending.operations)
+	}
+}
+
+func (b *batcher) flush(key string, pending *batch) {
+	b.mutex.Lock()
+	if b.batches[key] != pending {
+		// Batch has already been sent because it was full
+		b.mutex.Unlock()
+		return
+	}
+	delete(b.batches, key)
+	b.mutex.Unlock()
+
+	b.send(pending.operations)
+}
+
+// withBatching wraps the handler to send operations in batches.
+// If only one operation is collected in the batch it is executed by the handler as a regular request,
+// as are requests using incremental delivery.
+func (c Client) withBatching(handler Handler) Handler {
+	if c.options.batching == nil {
+		return handler
+	}
+
+	b := &batcher{
+		options: *c.options.batching,
+		send:    c.sendBatch,
+		batches: map[string]*batch{},
+	}
+
+	return func(ctx context.Context, request Request, responseOut interface{}) (*Response, error) {
+		if request.incremental != nil {
+			// Incremental responses cannot be delivered in batches
+			return handler(ctx, request, responseOut)
+		}
+
+		operation := &batchedOperation{
+			ctx:     ctx,
+			request: request,
+			result:  make(chan batchResult, 1),
+		}
+		b.add(operation)
+
+		select {
+		case result := <-operation.result:
+			if result.unbatched {
+				return handler(ctx, request, responseOut)
+			}
+			if result.err != nil {
+				return result.response, result.err
+			}
+			// Response is shared by operations in the batch, while errors and extensions are specific to the operation
+			response := *result.response
+			return &response, decodeResponse(bytes.NewReader(result.data), responseOut, &response)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// sendBatch sends operations that were not cancelled in a single request and delivers the results to the callers.
+// The request is cancelled only when contexts of all operations are done.
+func (c Client) sendBatch(operations []*batchedOperation) {
+	active := make([]*batchedOperation, 0, len(operations))
+	for _, operation := range operations {
+		if operation.ctx.Err() == nil {
+			active = append(active, operation)
+		}
+	}
+
+	switch len(active) {
+	case 0:
+		return
+	case 1:
+		active[0].result <- batchResult{unbatched: true}
+		return
+	}
+
+	// Values of the first operation context, like the trace span, are passed to the transport
+	ctx, cancel := context.WithCancel(detachedContext{Context: active[0].ctx})
+	defer cancel()
+	go func() {
+		for _, operation := range active {
+			select {
+			case <-operation.ctx.Done():
+			case <-ctx.Done():
+				return
+			}
+		}
+		cancel()
+	}()
+
+	start := time.Now()
+	response, results, err := c.doBatchRequest(ctx, active)
+	duration := time.Since(start)
+	if response != nil {
+		response.Duration = duration
+	}
+	c.logBatchResult(ctx, len(active), response, duration, err)
+
+	for i, operation := range active {
+		if err != nil {
+			operation.result <- batchResult{response: response, err: err}
+			continue
+		}
+		operation.result <- batchResult{response: response, data: results[i]}
+	}
+}
+
+func (c Client) doBatchRequest(ctx context.Context, operations []*batchedOperation) (*Response, []json.RawMessage, error) {
+	c.log(ctx, LogLevelDebug, "Executing batch", LogField{Key: LogFieldBatchSize, Value: len(operations)})
+
+	requestsData := make([]gqlRequestData, 0, len(operations))
+	for _, operation := range operations {
+		c.logRequest(operation.ctx, operation.request)
+		requestsData = append(requestsData, operation.request.toRequestData())
+	}
+
+	var requestBodyBuffer bytes.Buffer
+	if err := json.NewEncoder(&requestBodyBuffer).Encode(requestsData); err != nil {
+		return nil, nil, fmt.Errorf("failed to create http request: failed to encode body: %w", err)
+	}
+
+	idempotent := true
+	for _, operation := range operations {
+		idempotent = idempotent && operation.request.isIdempotent()
+	}
+
+	body := requestBodyBuffer.Bytes()
+	res, err := c.sendHTTPRequest(ctx, idempotent, func(endpoint string) (*http.Request, error) {
+		httpRequest, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http request: %w", err)
+		}
+
+		// All operations in the batch have the same headers, except trace context headers of the first operation
+		httpRequest.Header = operations[0].request.Header.Clone()
+		if httpRequest.Header == nil {
+			httpRequest.Header = http.Header{}
+		}
+		httpRequest.Header.Set(ContentTypeHeader, requestContentType)
+		httpRequest.Header.Set(AcceptHeader, acceptedMediaTypes)
+
+		return httpRequest, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.closeResponse(ctx, res.Body)
+	defer c.logResponse(ctx, res, c.captureBody(res))
+
+	response := newResponse(res)
+	if !isSuccessStatus(res.StatusCode) {
+		return response, nil, newHTTPError(res, response)
+	}
+
+	var results []json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&results); err != nil {
+		return response, nil, fmt.Errorf("failed to decode batch response body: %w", err)
+	}
+	if len(results) != len(operations) {
+		return response, nil, fmt.Errorf("batch response contains %d results, expected %d", len(results), len(operations))
+	}
+
+	return response, results, nil
+}
+
+func (c Client) logBatchResult(ctx context.Context, size int, response *Response, duration time.Duration, err error) {
+	fields := []LogField{
+		{Key: LogFieldBatchSize, Value: size},
+		{Key: LogFieldDuration, Value: duration},
+	}
+	if response != nil {
+		fields = append(fields, LogField{Key: LogFieldStatus, Value: response.StatusCode})
+	}
+
+	if err != nil {
+		c.log(ctx, LogLevelError, "Batch request failed", append(fields, LogField{Key: LogFieldError, Value: c.options.redaction.truncate(err.Error())})...)
+		return
+	}
+
+	c.log(ctx, LogLevelInfo, "Batch request executed", fields...)
+}
+
+// detachedContext has values of the parent context, but is not cancelled when the parent is.
+// The metrics recorder of the parent is not passed, as the batch request is not measured for any of its operations.
+type detachedContext struct {
+	context.Context
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	if key == (metricsRecorderKey{}) {
+		return nil
+	}
+
+	return c.Context.Value(key)
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+func (detachedContext) Err() error { return nil }
+
+// headersKey returns the key identifying the set of headers, regardless of the order of header names.
+// Trace context headers are not part of the key.
+func headersKey(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		if !isTraceContextHeader(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	for _, name := range names {
+		key.WriteString(name)
+		key.WriteString(": ")
+		key.WriteString(strings.Join(header[name], ", "))
+		key.WriteString("\n")
+	}
+
+	return key.String()
+}
+
+func isTraceContextHeader(name string) bool {
+	for _, traceHeader := range traceContextHeaders {
+		if http.CanonicalHeaderKey(name) == traceHeader {
+			return true
+		}
+	}
+
+	return false
+}
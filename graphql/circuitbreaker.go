@@ -0,0 +1,242 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without executing the request when the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of the circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed allows all requests
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all requests with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen allows limited number of trial requests to determine if the server recovered
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+// CircuitBreakerPolicy determines when the circuit breaker stops executing requests failing because the server is down.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failures after which the circuit is opened
+	FailureThreshold int
+	// OpenTimeout is the time after which the open circuit becomes half-open
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests is the number of trial requests allowed in the half-open state.
+	// The circuit is closed when all of them succeed and opened again when any of them fails.
+	HalfOpenMaxRequests int
+	// IsFailure determines if the error of the request is counted as failure.
+	// By default network errors and responses with 5xx status are failures, while GraphQL errors are not.
+	IsFailure func(err error) bool
+	// OnStateChange is called when the state of the circuit changes, for example to alert about unavailable server
+	OnStateChange func(from, to CircuitState)
+}
+
+// DefaultCircuitBreakerPolicy opens the circuit after 5 consecutive failures for 30 seconds
+var DefaultCircuitBreakerPolicy = CircuitBreakerPolicy{
+	FailureThreshold:    5,
+	OpenTimeout:         30 * time.Second,
+	HalfOpenMaxRequests: 1,
+}
+
+// isServerFailure determines if the request failed because the server is unavailable or malfunctioning
+func isServerFailure(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+type circuitBreaker struct {
+	policy        CircuitBreakerPolicy
+	onStateChange func(from, to CircuitState)
+	now           func() time.Time
+
+	mutex    sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	// generation changes with every state change to ignore results of requests allowed in the previous state
+	generation uint64
+	// trials is the number of trial requests allowed in the half-open state, successes is the number of succeeded ones
+	trials    int
+	successes int
+}
+
+func newCircuitBreaker(policy CircuitBreakerPolicy, onStateChange func(from, to CircuitState)) *circuitBreaker {
+	if policy.FailureThreshold < 1 {
+		policy.FailureThreshold = 1
+	}
+	if policy.HalfOpenMaxRequests < 1 {
+		policy.HalfOpenMaxRequests = 1
+	}
+	if policy.IsFailure == nil {
+		policy.IsFailure = isServerFailure
+	}
+
+	return &circuitBreaker{
+		policy:        policy,
+		onStateChange: onStateChange,
+		now:           time.Now,
+	}
+}
+
+// allow determines if the request can be executed, returning the generation to which its result should be reported
+func (b *circuitBreaker) allow() (uint64, error) {
+	b.mutex.Lock()
+	transition := b.updateState()
+	generation, allowed := b.generation, true
+
+	switch b.state {
+	case CircuitOpen:
+		allowed = false
+	case CircuitHalfOpen:
+		if b.trials >= b.policy.HalfOpenMaxRequests {
+			allowed = false
+		} else {
+			b.trials++
+		}
+	}
+	b.mutex.Unlock()
+
+	transition()
+	if !allowed {
+		return 0, ErrCircuitOpen
+	}
+
+	return generation, nil
+}
+
+// report records the result of the request allowed in the generation
+func (b *circuitBreaker) report(generation uint64, err error) {
+	failed := err != nil && b.policy.IsFailure(err)
+
+	b.mutex.Lock()
+	transition := func() {}
+	if generation == b.generation {
+		transition = b.recordResult(failed)
+	}
+	b.mutex.Unlock()
+
+	transition()
+}
+
+// cancel releases the trial request allowed in the generation without recording its result
+func (b *circuitBreaker) cancel(generation uint64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if generation == b.generation && b.state == CircuitHalfOpen {
+		b.trials--
+	}
+}
+
+func (b *circuitBreaker) recordResult(failed bool) func() {
+	switch b.state {
+	case CircuitClosed:
+		if !failed {
+			b.failures = 0
+			return func() {}
+		}
+		b.failures++
+		if b.failures >= b.policy.FailureThreshold {
+			return b.setState(CircuitOpen)
+		}
+	case CircuitHalfOpen:
+		if failed {
+			return b.setState(CircuitOpen)
+		}
+		b.successes++
+		if b.successes >= b.policy.HalfOpenMaxRequests {
+			return b.setState(CircuitClosed)
+		}
+	}
+
+	return func() {}
+}
+
+// updateState changes the state of the open circuit to half-open after the timeout
+func (b *circuitBreaker) updateState() func() {
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.policy.OpenTimeout {
+		return b.setState(CircuitHalfOpen)
+	}
+
+	return func() {}
+}
+
+// setState changes the state, returning the function notifying about the change,
+// which should be called after the mutex is unlocked
+func (b *circuitBreaker) setState(state CircuitState) func() {
+	from := b.state
+
+	b.state = state
+	b.generation++
+	b.failures = 0
+	b.trials = 0
+	b.successes = 0
+	if state == CircuitOpen {
+		b.openedAt = b.now()
+	}
+
+	return func() {
+		b.onStateChange(from, state)
+	}
+}
+
+// withCircuitBreaker wraps the handler to reject requests with ErrCircuitOpen when the circuit breaker is open
+func (c Client) withCircuitBreaker(handler Handler) Handler {
+	if c.options.circuitBreakerPolicy == nil {
+		return handler
+	}
+	policy := *c.options.circuitBreakerPolicy
+
+	breaker := newCircuitBreaker(policy, func(from, to CircuitState) {
+		c.log(context.Background(), LogLevelWarn, "Circuit breaker state changed",
+			LogField{Key: LogFieldCircuitFrom, Value: from.String()},
+			LogField{Key: LogFieldCircuitTo, Value: to.String()},
+		)
+		if policy.OnStateChange != nil {
+			policy.OnStateChange(from, to)
+		}
+	})
+
+	return func(ctx context.Context, request Request, responseOut interface{}) (*Response, error) {
+		generation, err := breaker.allow()
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := handler(ctx, request, responseOut)
+		if ctx.Err() != nil {
+			// Requests cancelled by the caller do not indicate if the server is available
+			breaker.cancel(generation)
+			return response, err
+		}
+		breaker.report(generation, err)
+
+		return response, err
+	}
+}
@@ -0,0 +1,85 @@
+package graphql
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	var transitions []CircuitState
+
+	breaker := newCircuitBreaker(CircuitBreakerPolicy{
+		FailureThreshold:    2,
+		OpenTimeout:         time.Second,
+		HalfOpenMaxRequests: 2,
+	}, func(from, to CircuitState) {
+		transitions = append(transitions, to)
+	})
+	breaker.now = func() time.Time {
+		return now
+	}
+
+	serverErr := &HTTPError{StatusCode: http.StatusBadGateway}
+
+	t.Run("should open circuit after consecutive failures", func(t *testing.T) {
+		allowAndReport(t, breaker, serverErr)
+		allowAndReport(t, breaker, nil)
+		allowAndReport(t, breaker, serverErr)
+		allowAndReport(t, breaker, GraphQLErrors{{Message: "not found"}})
+		assert.Equal(t, CircuitClosed, breaker.state)
+
+		allowAndReport(t, breaker, serverErr)
+		allowAndReport(t, breaker, &url.Error{Op: "Post", URL: "http://localhost", Err: errors.New("connection refused")})
+		assert.Equal(t, CircuitOpen, breaker.state)
+
+		_, err := breaker.allow()
+		assert.Equal(t, ErrCircuitOpen, err)
+	})
+
+	t.Run("should allow limited trial requests when half-open", func(t *testing.T) {
+		now = now.Add(time.Second)
+
+		first, err := breaker.allow()
+		require.NoError(t, err)
+		assert.Equal(t, CircuitHalfOpen, breaker.state)
+		second, err := breaker.allow()
+		require.NoError(t, err)
+		_, err = breaker.allow()
+		assert.Equal(t, ErrCircuitOpen, err)
+
+		breaker.cancel(second)
+		second, err = breaker.allow()
+		require.NoError(t, err)
+
+		breaker.report(first, nil)
+		assert.Equal(t, CircuitHalfOpen, breaker.state)
+		breaker.report(second, nil)
+		assert.Equal(t, CircuitClosed, breaker.state)
+	})
+
+	t.Run("should open circuit again when trial request fails", func(t *testing.T) {
+		allowAndReport(t, breaker, serverErr)
+		allowAndReport(t, breaker, serverErr)
+		now = now.Add(time.Second)
+
+		generation, err := breaker.allow()
+		require.NoError(t, err)
+		breaker.report(generation, serverErr)
+		assert.Equal(t, CircuitOpen, breaker.state)
+	})
+
+	assert.Equal(t, []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitClosed, CircuitOpen, CircuitHalfOpen, CircuitOpen}, transitions)
+}
+
+func allowAndReport(t *testing.T, breaker *circuitBreaker, err error) {
+	generation, allowErr := breaker.allow()
+	require.NoError(t, allowErr)
+	breaker.report(generation, err)
+}
@@ -1,59 +1,58 @@
 package graphql
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"strings"
+	"time"
 )
 
 type gqlRequestData struct {
-	Query         string                 `json:"query"`
+	Query         string                 `json:"query,omitempty"`
 	OperationName string                 `json:"operationName"`
 	Variables     map[string]interface{} `json:"variables"`
+	Extensions    map[string]interface{} `json:"extensions,omitempty"`
 }
 
 type gqlResponseData struct {
-	Data   interface{} `json:"data"`
-	Errors []graphErr  `json:"errors"`
-}
-
-type graphErr struct {
-	Message string
+	Data       interface{}            `json:"data"`
+	Errors     GraphQLErrors          `json:"errors"`
+	Extensions map[string]interface{} `json:"extensions"`
 }
 
 type resultWrapper struct {
 	Result interface{} `json:"result"`
 }
 
-func (e graphErr) Error() string {
-	return "graphql error: " + e.Message
-}
-
 type Client struct {
 	*options
-	endpoint string
+	endpoints *endpointPool
+	handler   Handler
 }
 
 func NewClient(endpoint string, option ...Option) *Client {
 	options := &options{
 		parserOptions: DefaultParserOptions,
 		httpClient:    &http.Client{},
-		logger:        nil,
+		redaction:     DefaultRedactionPolicy,
 	}
 
 	for _, opt := range option {
 		opt.apply(options)
 	}
 
-	return &Client{
-		endpoint: endpoint,
-		options:  options,
+	client := &Client{
+		endpoints: newEndpointPool(append([]string{endpoint}, options.endpoints...), options.endpointPolicy),
+		options:   options,
 	}
+	client.handler = chainMiddlewares(client.withCircuitBreaker(client.withRetry(client.withLimits(client.withAuth(client.withPersistedQueries(client.withBatching(client.doRequest)))))), options.middlewares)
+
+	return client
 }
 
 func (c Client) Execute(ctx context.Context, request Request, responseOut interface{}) error {
@@ -62,20 +61,39 @@ func (c Client) Execute(ctx context.Context, request Request, responseOut interf
 		return err
 	}
 
-	return c.executeRequest(ctx, request, responseOut)
+	_, err = c.executeRequest(ctx, request, responseOut)
+	return err
 }
 
-// TODO - try to get rid of result here?
+// ExecuteWithResponse executes the request decoding data of the response to responseOut and returns the Response
+// with GraphQL errors, extensions, HTTP status, headers and the duration of the last HTTP request.
+// The Response is returned also if the request failed, unless no response was received.
+func (c Client) ExecuteWithResponse(ctx context.Context, request Request, responseOut interface{}) (*Response, error) {
+	err := checkContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.executeRequest(ctx, request, responseOut)
+}
 
-// Run executes GraphQL operation
+// Run executes GraphQL operation decoding the result to result.
+// See RunAs for the variant returning the result of the type parameter.
 func (c Client) Run(ctx context.Context, operation Operation, result interface{}, header ...http.Header) error {
+	_, err := c.RunWithResponse(ctx, operation, result, header...)
+	return err
+}
+
+// RunWithResponse executes GraphQL operation decoding the result to result and returns the Response
+// as ExecuteWithResponse does.
+func (c Client) RunWithResponse(ctx context.Context, operation Operation, result interface{}, header ...http.Header) (*Response, error) {
 	if operation.Requested == nil {
 		operation.Requested = result
 	}
 
 	request, err := newRequest(operation, c.options.parserOptions, header...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	return c.wrapAndExecute(ctx, request, result)
@@ -83,6 +101,12 @@ func (c Client) Run(ctx context.Context, operation Operation, result interface{}
 
 // Query executes GraphQL query parsing provided input and requested type to query string
 func (c Client) Query(ctx context.Context, name string, input OperationInput, requested interface{}, header ...http.Header) error {
+	_, err := c.QueryWithResponse(ctx, name, input, requested, header...)
+	return err
+}
+
+// QueryWithResponse executes GraphQL query as Query does and returns the Response as ExecuteWithResponse does
+func (c Client) QueryWithResponse(ctx context.Context, name string, input OperationInput, requested interface{}, header ...http.Header) (*Response, error) {
 	operation := Operation{
 		Type:      Query,
 		Name:      name,
@@ -90,11 +114,17 @@ func (c Client) Query(ctx context.Context, name string, input OperationInput, re
 		Input:     input,
 	}
 
-	return c.Run(ctx, operation, &requested, header...)
+	return c.RunWithResponse(ctx, operation, &requested, header...)
 }
 
 // Mutate executes GraphQL mutation parsing provided input and requested type to query string
 func (c Client) Mutate(ctx context.Context, name string, input OperationInput, requested interface{}, header ...http.Header) error {
+	_, err := c.MutateWithResponse(ctx, name, input, requested, header...)
+	return err
+}
+
+// MutateWithResponse executes GraphQL mutation as Mutate does and returns the Response as ExecuteWithResponse does
+func (c Client) MutateWithResponse(ctx context.Context, name string, input OperationInput, requested interface{}, header ...http.Header) (*Response, error) {
 	operation := Operation{
 		Type:      Mutation,
 		Name:      name,
@@ -102,126 +132,279 @@ func (c Client) Mutate(ctx context.Context, name string, input OperationInput, r
 		Input:     input,
 	}
 
-	return c.Run(ctx, operation, &requested, header...)
+	return c.RunWithResponse(ctx, operation, &requested, header...)
 }
 
-func (c Client) wrapAndExecute(ctx context.Context, request Request, result interface{}) error {
+func (c Client) wrapAndExecute(ctx context.Context, request Request, result interface{}) (*Response, error) {
 	resultWrapper := resultWrapper{Result: result}
 	return c.executeRequest(ctx, request, &resultWrapper)
 }
 
-func (c Client) executeRequest(ctx context.Context, request Request, responseOut interface{}) error {
-	c.logRequest(request)
-
-	httpRequest, err := request.ToHttpRequest(c.endpoint)
+func (c Client) executeRequest(ctx context.Context, request Request, responseOut interface{}) (*Response, error) {
+	request, err := c.applyHeaders(ctx, request)
 	if err != nil {
-		return fmt.Errorf("failed to create http request: %w", err)
+		return nil, err
+	}
+
+	if c.options.metrics == nil {
+		return c.handler(ctx, request, responseOut)
 	}
 
-	httpRequest = httpRequest.WithContext(ctx)
+	ctx, recorder := withMetricsRecorder(ctx)
+	start := time.Now()
+	response, err := c.handler(ctx, request, responseOut)
+	c.observeOperation(ctx, request, recorder, response, time.Since(start), err)
 
-	res, err := c.options.httpClient.Do(httpRequest)
-	if err != nil {
-		return fmt.Errorf("error while executing request: %w", err)
+	return response, err
+}
+
+// applyHeaders adds default headers of the client to the request
+func (c Client) applyHeaders(ctx context.Context, request Request) (Request, error) {
+	if len(c.options.headers) == 0 && len(c.options.headerProviders) == 0 {
+		return request, nil
 	}
-	defer c.closeResponse(res.Body)
 
-	c.logResponse(res)
+	headers := make([]http.Header, 0, len(c.options.headerProviders)+2)
+	headers = append(headers, c.options.headers)
 
-	if res.StatusCode != http.StatusOK {
-		bodyBytes, err := ioutil.ReadAll(res.Body)
-		if err != nil || len(bodyBytes) == 0 {
-			return fmt.Errorf("received unexpected response status: %s", res.Status)
+	for _, provider := range c.options.headerProviders {
+		header, err := provider(ctx)
+		if err != nil {
+			return request, fmt.Errorf("failed to get headers from provider: %w", err)
 		}
+		headers = append(headers, header)
+	}
 
-		errorResponse := gqlResponseData{}
-		err = json.Unmarshal(bodyBytes, &errorResponse)
-		if err != nil || len(errorResponse.Errors) == 0 {
-			return fmt.Errorf("received unexpected response status: %s. Response body: %s", res.Status, string(bodyBytes))
-		}
+	request.Header = overrideHeaders(append(headers, request.Header)...)
 
-		return parseErrorResponse(errorResponse.Errors)
+	return request, nil
+}
+
+func (c Client) doRequest(ctx context.Context, request Request, responseOut interface{}) (*Response, error) {
+	c.logRequest(ctx, request)
+
+	start := time.Now()
+	response, err := c.sendRequest(ctx, request, responseOut)
+	duration := time.Since(start)
+	if response != nil {
+		response.Duration = duration
 	}
+	c.logResult(ctx, request, response, duration, err)
 
-	responseData := gqlResponseData{
-		Data:   responseOut,
-		Errors: []graphErr{},
+	return response, err
+}
+
+func (c Client) sendRequest(ctx context.Context, request Request, responseOut interface{}) (*Response, error) {
+	res, err := c.sendHTTPRequest(ctx, request.isIdempotent(), func(endpoint string) (*http.Request, error) {
+		httpRequest, err := c.newHTTPRequest(request, endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http request: %w", err)
+		}
+
+		if request.incremental != nil {
+			httpRequest.Header.Set(AcceptHeader, incrementalAcceptedMediaTypes)
+		}
+
+		return httpRequest, nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	defer c.closeResponse(ctx, res.Body)
+	defer c.logResponse(ctx, res, c.captureBody(res))
 
-	if err := json.NewDecoder(res.Body).Decode(&responseData); err != nil {
-		return fmt.Errorf("failed to decode response body: %w", err)
+	response := newResponse(res)
+	if !isSuccessStatus(res.StatusCode) {
+		return response, newHTTPError(res, response)
 	}
 
-	if len(responseData.Errors) > 0 {
-		return parseErrorResponse(responseData.Errors)
+	if request.incremental != nil {
+		return response, decodeIncrementalResponse(res, response, responseOut, request.incremental)
 	}
 
-	return nil
+	return response, decodeResponse(res.Body, responseOut, response)
 }
 
-func parseErrorResponse(gqlErrors []graphErr) error {
-	errorString := ""
+// sendHTTPRequest sends the request created by newRequest to one of the endpoints, compressing its body
+// and decompressing the response if needed. Each request waits for the rate and concurrency limits of the client.
+// If the endpoint is unavailable and the request is idempotent, it is sent to the next endpoint.
+func (c Client) sendHTTPRequest(ctx context.Context, idempotent bool, newRequest func(endpoint string) (*http.Request, error)) (*http.Response, error) {
+	candidates := c.endpoints.candidates()
+
+	for i, endpoint := range candidates {
+		httpRequest, err := newRequest(endpoint.url)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.compressRequest(httpRequest); err != nil {
+			return nil, fmt.Errorf("failed to create http request: %w", err)
+		}
+		c.acceptEncoding(httpRequest)
+
+		release, err := c.acquireLimits(ctx)
+		if err != nil {
+			return nil, err
+		}
 
-	for _, err := range gqlErrors {
-		errorString += fmt.Sprintf("%s%s\n", errorString, err.Error())
+		start := time.Now()
+		res, err := c.options.httpClient.Do(httpRequest.WithContext(ctx))
+		if err != nil {
+			release()
+		} else {
+			res.Body = &releasingBody{ReadCloser: res.Body, release: release}
+		}
+		if err != nil && ctx.Err() != nil {
+			// Requests cancelled by the caller do not indicate if the endpoint is available
+			return nil, fmt.Errorf("error while executing request: %w", err)
+		}
+
+		unavailable := err != nil || res.StatusCode >= 500
+		c.endpoints.report(endpoint, time.Since(start), unavailable)
+
+		if unavailable && idempotent && i < len(candidates)-1 {
+			fields := []LogField{{Key: LogFieldEndpoint, Value: endpoint.url}}
+			if err != nil {
+				fields = append(fields, LogField{Key: LogFieldError, Value: err.Error()})
+			} else {
+				fields = append(fields, LogField{Key: LogFieldStatus, Value: res.StatusCode})
+				c.closeResponse(ctx, res.Body)
+			}
+			c.log(ctx, LogLevelWarn, "Endpoint unavailable, sending request to the next one", fields...)
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error while executing request: %w", err)
+		}
+
+		recorderFromContext(ctx).recordRequest(httpRequest, res)
+
+		if err := c.decompressResponse(res); err != nil {
+			c.closeResponse(ctx, res.Body)
+			return nil, err
+		}
+
+		return res, nil
 	}
 
-	return fmt.Errorf(strings.TrimSuffix(errorString, "\n"))
+	return nil, fmt.Errorf("no endpoints configured")
 }
 
-func checkContext(ctx context.Context) error {
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-		return nil
+func newResponse(res *http.Response) *Response {
+	return &Response{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		MediaType:  responseMediaType(res.Header),
 	}
 }
 
-func (c Client) logRequest(request Request) {
-	if c.options.logger != nil {
-		c.options.logger(fmt.Sprintf("Executing request\nHeaders: %s\nQuery: %s", request.Header, request.Query))
+// responseMediaType returns the media type of the response without parameters or empty string if it is not set
+func responseMediaType(header http.Header) string {
+	mediaType, _, err := mime.ParseMediaType(header.Get(ContentTypeHeader))
+	if err != nil {
+		return ""
 	}
+
+	return mediaType
 }
 
-func (c Client) logResponse(response *http.Response) {
-	if c.options.logger != nil {
-		r1, r2, err := drainBody(response.Body)
-		if err != nil {
-			c.options.logger("Failed to log response body: failed to copy response body")
-			return
-		}
-		response.Body = r1
+// isSuccessStatus determines if the response has the status for which the body should be decoded as GraphQL response.
+// Servers following GraphQL over HTTP specification respond with 2xx status if the request was executed.
+func isSuccessStatus(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
 
-		buff := make([]byte, response.ContentLength)
-		_, err = r2.Read(buff)
-		if err != nil {
-			c.options.logger("Failed to log response body: failed to read response body copy")
-			return
-		}
+// newHTTPError creates HTTPError from the response with unexpected status.
+// GraphQL errors are parsed from the body only if it has JSON media type, as responses with other types,
+// such as HTML error pages returned by proxies, do not contain GraphQL response.
+// With application/graphql-response+json the body is expected to contain GraphQL request errors,
+// while with application/json errors are parsed on the best-effort basis.
+// Parsed errors and extensions are also set on the response.
+func newHTTPError(res *http.Response, response *Response) *HTTPError {
+	mediaType := response.MediaType
+	httpErr := &HTTPError{
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+		MediaType:  mediaType,
+	}
 
-		c.options.logger(fmt.Sprintf("Response status: %s\nBody:\n%s", response.Status, string(buff)))
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil || len(bodyBytes) == 0 {
+		return httpErr
 	}
+	httpErr.Body = string(bodyBytes)
+
+	if !isJSONMediaType(mediaType) {
+		return httpErr
+	}
+
+	errorResponse := gqlResponseData{}
+	err = json.Unmarshal(bodyBytes, &errorResponse)
+	if err == nil {
+		httpErr.Errors = errorResponse.Errors
+		response.Errors = errorResponse.Errors
+		response.Extensions = errorResponse.Extensions
+	}
+
+	return httpErr
+}
+
+// isJSONMediaType determines if the media type is JSON, treating missing media type as JSON for compatibility
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "" || mediaType == MediaTypeJSON || strings.HasSuffix(mediaType, "+json")
 }
 
-func drainBody(b io.ReadCloser) (r1, r2 io.ReadCloser, err error) {
-	if b == http.NoBody {
-		// No copying needed. Preserve the magic sentinel meaning of NoBody.
-		return http.NoBody, http.NoBody, nil
+// decodeResponse decodes data of the GraphQL response to responseOut, returning GraphQL errors if present.
+// Errors and extensions are also set on the response.
+func decodeResponse(body io.Reader, responseOut interface{}, response *Response) error {
+	responseData := gqlResponseData{
+		Data:   responseOut,
+		Errors: GraphQLErrors{},
+	}
+
+	if err := json.NewDecoder(body).Decode(&responseData); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	if len(responseData.Errors) > 0 {
+		response.Errors = responseData.Errors
 	}
-	var buf bytes.Buffer
-	if _, err = buf.ReadFrom(b); err != nil {
-		return nil, b, err
+	response.Extensions = responseData.Extensions
+
+	if len(responseData.Errors) > 0 {
+		return responseData.Errors
 	}
-	if err = b.Close(); err != nil {
-		return nil, b, err
+
+	return nil
+}
+
+// newHTTPRequest creates GET request for queries if enabled and the URL does not exceed the limit, otherwise POST
+func (c Client) newHTTPRequest(request Request, endpoint string) (*http.Request, error) {
+	if c.options.getQueries && request.OperationType() == Query {
+		httpRequest, err := request.ToHttpGetRequest(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if len(httpRequest.URL.String()) <= c.options.maxURLLength {
+			return httpRequest, nil
+		}
+	}
+
+	return request.ToHttpRequest(endpoint)
+}
+
+func checkContext(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
 	}
-	return ioutil.NopCloser(&buf), ioutil.NopCloser(bytes.NewReader(buf.Bytes())), nil
 }
 
-func (c Client) closeResponse(closer io.ReadCloser) {
+func (c Client) closeResponse(ctx context.Context, closer io.ReadCloser) {
 	err := closer.Close()
 	if err != nil {
-		c.options.logger(fmt.Sprintf("Warning: failed to close response body: %s", err.Error()))
+		c.log(ctx, LogLevelWarn, "Failed to close response body", LogField{Key: LogFieldError, Value: err.Error()})
 	}
 }
@@ -0,0 +1,187 @@
+package graphql
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	AcceptEncodingHeader  = "Accept-Encoding"
+	ContentEncodingHeader = "Content-Encoding"
+)
+
+// Codec compresses and decompresses bodies with the content encoding.
+// Codecs for encodings other than gzip and deflate, such as br or zstd, can be provided by implementing the interface.
+type Codec interface {
+	// Encoding returns the name of the encoding used in Content-Encoding and Accept-Encoding headers
+	Encoding() string
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	// GzipCodec compresses bodies with gzip
+	GzipCodec Codec = gzipCodec{}
+	// DeflateCodec compresses bodies with deflate, which in HTTP is the zlib format
+	DeflateCodec Codec = deflateCodec{}
+
+	defaultCodecs = []Codec{GzipCodec, DeflateCodec}
+)
+
+type gzipCodec struct{}
+
+func (gzipCodec) Encoding() string {
+	return "gzip"
+}
+
+func (gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+type deflateCodec struct{}
+
+func (deflateCodec) Encoding() string {
+	return "deflate"
+}
+
+func (deflateCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zlib.NewWriter(w), nil
+}
+
+func (deflateCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return zlib.NewReader(r)
+}
+
+type requestCompression struct {
+	codec     Codec
+	threshold int
+}
+
+// compressRequest compresses the body of the request if it is larger than the threshold
+func (c Client) compressRequest(httpRequest *http.Request) error {
+	compression := c.options.requestCompression
+	if compression == nil || httpRequest.Body == nil || httpRequest.ContentLength < int64(compression.threshold) {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(httpRequest.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	var compressed bytes.Buffer
+	writer, err := compression.codec.NewWriter(&compressed)
+	if err != nil {
+		return fmt.Errorf("failed to create %s writer: %w", compression.codec.Encoding(), err)
+	}
+	if _, err := writer.Write(body); err != nil {
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+
+	compressedBody := compressed.Bytes()
+	httpRequest.Body = ioutil.NopCloser(bytes.NewReader(compressedBody))
+	httpRequest.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(compressedBody)), nil
+	}
+	httpRequest.ContentLength = int64(len(compressedBody))
+	httpRequest.Header.Set(ContentEncodingHeader, compression.codec.Encoding())
+
+	return nil
+}
+
+// acceptEncoding sets Accept-Encoding header of the request if response compression is enabled.
+// Setting the header disables transparent decompression of the transport, therefore responses are decompressed
+// by the client.
+func (c Client) acceptEncoding(httpRequest *http.Request) {
+	if len(c.options.responseCodecs) == 0 {
+		return
+	}
+
+	encodings := make([]string, 0, len(c.options.responseCodecs))
+	for _, codec := range c.options.responseCodecs {
+		encodings = append(encodings, codec.Encoding())
+	}
+	httpRequest.Header.Set(AcceptEncodingHeader, strings.Join(encodings, ", "))
+}
+
+// decompressResponse decodes the body of the response according to its Content-Encoding.
+// Responses are decompressed with configured codecs or built-in gzip and deflate codecs, which allows to
+// handle compressed responses also when the transport does not decompress them.
+func (c Client) decompressResponse(res *http.Response) error {
+	contentEncoding := res.Header.Get(ContentEncodingHeader)
+	if contentEncoding == "" {
+		return nil
+	}
+
+	encodings := strings.Split(contentEncoding, ",")
+	body := &decompressedBody{Reader: res.Body, closers: []io.Closer{res.Body}}
+
+	// Encodings are listed in the order in which they were applied
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.ToLower(strings.TrimSpace(encodings[i]))
+		if encoding == "identity" || encoding == "" {
+			continue
+		}
+
+		codec, ok := c.responseCodec(encoding)
+		if !ok {
+			return fmt.Errorf("unsupported response content encoding: %s", encoding)
+		}
+
+		reader, err := codec.NewReader(body.Reader)
+		if err != nil {
+			return fmt.Errorf("failed to decompress response body with %s: %w", encoding, err)
+		}
+		body.Reader = reader
+		body.closers = append(body.closers, reader)
+	}
+
+	res.Body = body
+	res.Header.Del(ContentEncodingHeader)
+	res.ContentLength = -1
+	res.Uncompressed = true
+
+	return nil
+}
+
+func (c Client) responseCodec(encoding string) (Codec, bool) {
+	for _, codecs := range [][]Codec{c.options.responseCodecs, defaultCodecs} {
+		for _, codec := range codecs {
+			if strings.ToLower(codec.Encoding()) == encoding {
+				return codec, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// decompressedBody reads decompressed response body closing all readers when closed
+type decompressedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (b *decompressedBody) Close() error {
+	var err error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		if closeErr := b.closers[i].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
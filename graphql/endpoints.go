@@ -0,0 +1,135 @@
+package graphql
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// EndpointStrategy determines the order in which endpoints are used
+type EndpointStrategy int
+
+const (
+	// RoundRobin distributes requests evenly between endpoints
+	RoundRobin EndpointStrategy = iota
+	// PrimaryFallback sends requests to the first healthy endpoint in the order in which they were provided
+	PrimaryFallback
+	// LeastLatency sends requests to the healthy endpoint with the lowest average latency
+	LeastLatency
+)
+
+// EndpointPolicy determines how requests are distributed between endpoints and when endpoints are skipped.
+type EndpointPolicy struct {
+	Strategy EndpointStrategy
+	// FailureThreshold is the number of consecutive failures after which the endpoint is marked as unhealthy.
+	// Network errors and responses with 5xx status are failures. DefaultEndpointPolicy.FailureThreshold is used if it is not set.
+	FailureThreshold int
+	// UnhealthyTimeout is the time for which the unhealthy endpoint is skipped, unless all endpoints are unhealthy.
+	// DefaultEndpointPolicy.UnhealthyTimeout is used if it is not set.
+	UnhealthyTimeout time.Duration
+}
+
+// DefaultEndpointPolicy distributes requests with round-robin, skipping endpoints failing 3 times in a row for 30 seconds
+var DefaultEndpointPolicy = EndpointPolicy{
+	Strategy:         RoundRobin,
+	FailureThreshold: 3,
+	UnhealthyTimeout: 30 * time.Second,
+}
+
+// latencySmoothing is the weight of the latest measurement in the moving average of the endpoint latency
+const latencySmoothing = 0.2
+
+type endpoint struct {
+	url string
+
+	failures       int
+	unhealthyUntil time.Time
+	// latency is the exponential moving average of the latency, zero if it was not measured yet
+	latency time.Duration
+}
+
+// endpointPool orders endpoints according to the strategy and tracks their health
+type endpointPool struct {
+	policy EndpointPolicy
+	now    func() time.Time
+
+	mutex     sync.Mutex
+	endpoints []*endpoint
+	next      int
+}
+
+func newEndpointPool(urls []string, policy EndpointPolicy) *endpointPool {
+	if policy.FailureThreshold < 1 {
+		policy.FailureThreshold = DefaultEndpointPolicy.FailureThreshold
+	}
+	if policy.UnhealthyTimeout <= 0 {
+		policy.UnhealthyTimeout = DefaultEndpointPolicy.UnhealthyTimeout
+	}
+
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, url := range urls {
+		endpoints = append(endpoints, &endpoint{url: url})
+	}
+
+	return &endpointPool{
+		policy:    policy,
+		now:       time.Now,
+		endpoints: endpoints,
+	}
+}
+
+// candidates returns endpoints in the order in which they should be tried.
+// Unhealthy endpoints are placed at the end, so they are used only if all other endpoints fail.
+func (p *endpointPool) candidates() []*endpoint {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	candidates := make([]*endpoint, 0, len(p.endpoints))
+	switch p.policy.Strategy {
+	case RoundRobin:
+		candidates = append(candidates, p.endpoints[p.next:]...)
+		candidates = append(candidates, p.endpoints[:p.next]...)
+		p.next = (p.next + 1) % len(p.endpoints)
+	case LeastLatency:
+		candidates = append(candidates, p.endpoints...)
+		// Endpoints without measured latency are tried first, so that their latency is measured
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].latency < candidates[j].latency
+		})
+	default:
+		candidates = append(candidates, p.endpoints...)
+	}
+
+	now := p.now()
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].healthy(now) && !candidates[j].healthy(now)
+	})
+
+	return candidates
+}
+
+// report records the result of the request sent to the endpoint
+func (p *endpointPool) report(e *endpoint, latency time.Duration, failed bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if failed {
+		e.failures++
+		if e.failures >= p.policy.FailureThreshold {
+			e.unhealthyUntil = p.now().Add(p.policy.UnhealthyTimeout)
+		}
+		return
+	}
+
+	e.failures = 0
+	e.unhealthyUntil = time.Time{}
+	if e.latency == 0 {
+		e.latency = latency
+		return
+	}
+	e.latency = time.Duration(latencySmoothing*float64(latency) + (1-latencySmoothing)*float64(e.latency))
+}
+
+func (e *endpoint) healthy(now time.Time) bool {
+	return !now.Before(e.unhealthyUntil)
+}
@@ -0,0 +1,94 @@
+package graphql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointPool_Candidates(t *testing.T) {
+	urls := []string{"http://a", "http://b", "http://c"}
+
+	t.Run("round robin", func(t *testing.T) {
+		pool := newEndpointPool(urls, EndpointPolicy{Strategy: RoundRobin})
+
+		assert.Equal(t, []string{"http://a", "http://b", "http://c"}, candidateURLs(pool))
+		assert.Equal(t, []string{"http://b", "http://c", "http://a"}, candidateURLs(pool))
+		assert.Equal(t, []string{"http://c", "http://a", "http://b"}, candidateURLs(pool))
+		assert.Equal(t, []string{"http://a", "http://b", "http://c"}, candidateURLs(pool))
+	})
+
+	t.Run("primary and fallback", func(t *testing.T) {
+		pool := newEndpointPool(urls, EndpointPolicy{Strategy: PrimaryFallback})
+
+		assert.Equal(t, []string{"http://a", "http://b", "http://c"}, candidateURLs(pool))
+		assert.Equal(t, []string{"http://a", "http://b", "http://c"}, candidateURLs(pool))
+	})
+
+	t.Run("least latency", func(t *testing.T) {
+		pool := newEndpointPool(urls, EndpointPolicy{Strategy: LeastLatency})
+		pool.report(pool.endpoints[0], 300*time.Millisecond, false)
+		pool.report(pool.endpoints[1], 100*time.Millisecond, false)
+
+		assert.Equal(t, []string{"http://c", "http://b", "http://a"}, candidateURLs(pool), "should try endpoint without measured latency first")
+
+		pool.report(pool.endpoints[2], 200*time.Millisecond, false)
+		assert.Equal(t, []string{"http://b", "http://c", "http://a"}, candidateURLs(pool))
+
+		for i := 0; i < 10; i++ {
+			pool.report(pool.endpoints[1], 500*time.Millisecond, false)
+		}
+		assert.Equal(t, []string{"http://c", "http://a", "http://b"}, candidateURLs(pool))
+	})
+
+	t.Run("should skip unhealthy endpoints until timeout", func(t *testing.T) {
+		now := time.Now()
+		pool := newEndpointPool(urls, EndpointPolicy{Strategy: PrimaryFallback, FailureThreshold: 2, UnhealthyTimeout: time.Minute})
+		pool.now = func() time.Time {
+			return now
+		}
+
+		pool.report(pool.endpoints[0], 0, true)
+		assert.Equal(t, []string{"http://a", "http://b", "http://c"}, candidateURLs(pool))
+
+		pool.report(pool.endpoints[0], 0, true)
+		pool.report(pool.endpoints[1], 0, true)
+		pool.report(pool.endpoints[1], 0, true)
+		assert.Equal(t, []string{"http://c", "http://a", "http://b"}, candidateURLs(pool))
+
+		now = now.Add(time.Minute)
+		assert.Equal(t, []string{"http://a", "http://b", "http://c"}, candidateURLs(pool))
+	})
+
+	t.Run("should skip unhealthy endpoints according to default policy if it is not set", func(t *testing.T) {
+		now := time.Now()
+		pool := newEndpointPool(urls, EndpointPolicy{Strategy: PrimaryFallback})
+		pool.now = func() time.Time {
+			return now
+		}
+
+		for i := 0; i < DefaultEndpointPolicy.FailureThreshold-1; i++ {
+			pool.report(pool.endpoints[0], 0, true)
+		}
+		assert.Equal(t, []string{"http://a", "http://b", "http://c"}, candidateURLs(pool))
+
+		pool.report(pool.endpoints[0], 0, true)
+		assert.Equal(t, []string{"http://b", "http://c", "http://a"}, candidateURLs(pool))
+
+		now = now.Add(DefaultEndpointPolicy.UnhealthyTimeout - time.Second)
+		assert.Equal(t, []string{"http://b", "http://c", "http://a"}, candidateURLs(pool))
+
+		now = now.Add(time.Second)
+		assert.Equal(t, []string{"http://a", "http://b", "http://c"}, candidateURLs(pool))
+	})
+}
+
+func candidateURLs(pool *endpointPool) []string {
+	var urls []string
+	for _, endpoint := range pool.candidates() {
+		urls = append(urls, endpoint.url)
+	}
+
+	return urls
+}
@@ -0,0 +1,80 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	errorCodeExtension = "code"
+)
+
+// GraphQLError is a single error from the errors list of the GraphQL response
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (e GraphQLError) Error() string {
+	return "graphql error: " + e.Message
+}
+
+// Code returns the error code from the extensions of the error or empty string if it is not present
+func (e GraphQLError) Code() string {
+	code, _ := e.Extensions[errorCodeExtension].(string)
+	return code
+}
+
+// GraphQLErrors is returned when the GraphQL response contains errors
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// HasCode determines if any of the errors has the specified code in the extensions
+func (e GraphQLErrors) HasCode(code string) bool {
+	for _, err := range e {
+		if err.Code() == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HTTPError is returned when the server responds with an unexpected status code.
+// If the response body contains GraphQL errors they are available in Errors.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	// MediaType is the media type of the response without parameters
+	MediaType string
+	Body      string
+	Errors    GraphQLErrors
+}
+
+func (e *HTTPError) Error() string {
+	if len(e.Errors) > 0 {
+		return e.Errors.Error()
+	}
+	if e.Body == "" {
+		return fmt.Sprintf("received unexpected response status: %s", e.Status)
+	}
+
+	return fmt.Sprintf("received unexpected response status: %s. Response body: %s", e.Status, e.Body)
+}
+
+func (e *HTTPError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e.Errors
+}
@@ -0,0 +1,209 @@
+package graphql
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// structField describes a field of the struct as seen by the parsers.
+// Fields of embedded structs are promoted to the embedding struct the same way as encoding/json does it.
+type structField struct {
+	name   string
+	tagged bool
+	index  []int
+	typ    reflect.Type
+}
+
+var structFieldsCache sync.Map // map[reflect.Type][]structField
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// isMarshaledStruct determines if the struct type marshals itself to JSON or text, such as time.Time.
+// Such types are custom scalars rather than objects, so their fields are not parsed.
+func isMarshaledStruct(t reflect.Type) bool {
+	for _, marshalerType := range []reflect.Type{jsonMarshalerType, textMarshalerType} {
+		if t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// structFields returns fields of the struct type that should be included in the query or input.
+func structFields(t reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]structField)
+	}
+
+	fields, _ := structFieldsCache.LoadOrStore(t, typeFields(t))
+	return fields.([]structField)
+}
+
+// typeFields resolves fields of the struct type following the rules of encoding/json:
+// unexported fields are skipped, anonymous struct fields without the name in json tag are flattened,
+// if multiple fields have the same name the one with the shallowest depth wins, tagged fields win over untagged ones,
+// and if there is still a conflict all of the fields are omitted.
+func typeFields(t reflect.Type) []structField {
+	var (
+		current []structField
+		next    = []structField{{typ: t}}
+
+		count     map[reflect.Type]int
+		nextCount map[reflect.Type]int
+
+		visited = map[reflect.Type]bool{}
+
+		fields []structField
+	)
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+
+				if sf.Anonymous {
+					embeddedType := sf.Type
+					if embeddedType.Kind() == reflect.Ptr {
+						embeddedType = embeddedType.Elem()
+					}
+					// Embedded structs of unexported types are not skipped as they may have exported fields
+					if isUnexported(sf) && embeddedType.Kind() != reflect.Struct {
+						continue
+					}
+				} else if isUnexported(sf) {
+					continue
+				}
+
+				tag := sf.Tag.Get(jsonTagKey)
+				if tag == "-" {
+					continue
+				}
+				name := parseTagName(tag)
+
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				fieldType := sf.Type
+				if fieldType.Name() == "" && fieldType.Kind() == reflect.Ptr {
+					fieldType = fieldType.Elem()
+				}
+
+				if name != "" || !sf.Anonymous || fieldType.Kind() != reflect.Struct {
+					tagged := name != ""
+					if name == "" {
+						name = sf.Name
+					}
+
+					fields = append(fields, structField{name: name, tagged: tagged, index: index, typ: sf.Type})
+					if count[f.typ] > 1 {
+						// If the struct was embedded multiple times on the same level add duplicate
+						// so that the field is removed when resolving conflicts
+						fields = append(fields, fields[len(fields)-1])
+					}
+					continue
+				}
+
+				nextCount[fieldType]++
+				if nextCount[fieldType] == 1 {
+					next = append(next, structField{name: fieldType.Name(), index: index, typ: fieldType})
+				}
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
+		}
+		if fields[i].tagged != fields[j].tagged {
+			return fields[i].tagged
+		}
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+
+	dominantFields := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != fields[i].name {
+				break
+			}
+		}
+
+		if dominant, ok := dominantField(fields[i : i+advance]); ok {
+			dominantFields = append(dominantFields, dominant)
+		}
+	}
+
+	sort.Slice(dominantFields, func(i, j int) bool {
+		return lessIndex(dominantFields[i].index, dominantFields[j].index)
+	})
+
+	return dominantFields
+}
+
+// dominantField returns the field that takes precedence over other fields with the same name.
+// Fields are expected to be sorted by depth and then by tagged flag.
+func dominantField(fields []structField) (structField, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tagged == fields[1].tagged {
+		return structField{}, false
+	}
+
+	return fields[0], true
+}
+
+func lessIndex(a, b []int) bool {
+	for k := range a {
+		if k >= len(b) {
+			return false
+		}
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+
+	return len(a) < len(b)
+}
+
+func isUnexported(sf reflect.StructField) bool {
+	return sf.PkgPath != ""
+}
+
+func parseTagName(tag string) string {
+	if idx := strings.Index(tag, ","); idx != -1 {
+		return tag[:idx]
+	}
+
+	return tag
+}
+
+// fieldByIndex returns the nested field of the struct value.
+// If the field is promoted through a nil embedded pointer ok is false.
+func fieldByIndex(reflectVal reflect.Value, index []int) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 && reflectVal.Kind() == reflect.Ptr {
+			if reflectVal.IsNil() {
+				return reflect.Value{}, false
+			}
+			reflectVal = reflectVal.Elem()
+		}
+		reflectVal = reflectVal.Field(idx)
+	}
+
+	return reflectVal, true
+}
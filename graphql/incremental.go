@@ -0,0 +1,268 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+)
+
+const (
+	mediaTypeMultipartMixed = "multipart/mixed"
+
+	// incrementalAcceptedMediaTypes prefers incremental delivery, while allowing regular responses
+	// if the server does not support it
+	incrementalAcceptedMediaTypes = mediaTypeMultipartMixed + ";deferSpec=20220824, " + acceptedMediaTypes
+)
+
+// IncrementalResult describes the state of the result of the operation using @defer or @stream directives,
+// after the payload received from the server was applied to it
+type IncrementalResult struct {
+	// Initial is true for the first payload, which contains the data that was not deferred
+	Initial bool
+	// Completed is true after the last payload was applied and the result is complete
+	Completed bool
+	// Errors contains GraphQL errors received so far
+	Errors GraphQLErrors
+}
+
+// IncrementalObserver is called after each payload of the incremental response is applied to the result.
+// The result can be safely read only during the call.
+type IncrementalObserver func(result IncrementalResult)
+
+type incrementalPayload struct {
+	Data        interface{}            `json:"data"`
+	Errors      GraphQLErrors          `json:"errors"`
+	Extensions  map[string]interface{} `json:"extensions"`
+	Incremental []incrementalPatch     `json:"incremental"`
+	HasNext     bool                   `json:"hasNext"`
+}
+
+// incrementalPatch is the deferred fragment, if Data is set, or the streamed list items
+type incrementalPatch struct {
+	Data   interface{}   `json:"data"`
+	Items  []interface{} `json:"items"`
+	Path   []interface{} `json:"path"`
+	Errors GraphQLErrors `json:"errors"`
+}
+
+// ExecuteIncremental executes the request using @defer or @stream directives.
+// Payloads of the multipart/mixed response are applied to responseOut as they arrive and the observer is notified
+// after each of them. If the server does not support incremental delivery the complete result is decoded at once.
+func (c Client) ExecuteIncremental(ctx context.Context, request Request, responseOut interface{}, observer IncrementalObserver) error {
+	err := checkContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if observer == nil {
+		observer = func(IncrementalResult) {}
+	}
+	request.incremental = observer
+
+	_, err = c.executeRequest(ctx, request, responseOut)
+	return err
+}
+
+// decodeIncrementalResponse decodes the response of the request using incremental delivery.
+// Errors and extensions received in all payloads are set on the response.
+func decodeIncrementalResponse(res *http.Response, response *Response, responseOut interface{}, observer IncrementalObserver) error {
+	if response.MediaType != mediaTypeMultipartMixed {
+		err := decodeResponse(res.Body, responseOut, response)
+
+		var gqlErrs GraphQLErrors
+		if err == nil || errors.As(err, &gqlErrs) {
+			observer(IncrementalResult{Initial: true, Completed: true, Errors: gqlErrs})
+		}
+		return err
+	}
+
+	_, params, err := mime.ParseMediaType(res.Header.Get(ContentTypeHeader))
+	if err != nil {
+		return fmt.Errorf("failed to parse response content type: %w", err)
+	}
+
+	reader := multipart.NewReader(res.Body, params["boundary"])
+
+	var (
+		data    interface{}
+		gqlErrs GraphQLErrors
+		initial = true
+	)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return fmt.Errorf("incremental response ended before the result was completed")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read incremental response: %w", err)
+		}
+
+		var payload incrementalPayload
+		decoder := json.NewDecoder(part)
+		decoder.UseNumber()
+		if err := decoder.Decode(&payload); err != nil {
+			if err == io.EOF {
+				// Empty parts are sent by some servers to keep the connection alive
+				continue
+			}
+			return fmt.Errorf("failed to decode incremental response payload: %w", err)
+		}
+
+		if initial {
+			data = payload.Data
+		}
+		for _, patch := range payload.Incremental {
+			if data, err = patch.apply(data); err != nil {
+				return fmt.Errorf("failed to apply incremental response payload: %w", err)
+			}
+			gqlErrs = append(gqlErrs, patch.Errors...)
+		}
+		gqlErrs = append(gqlErrs, payload.Errors...)
+		response.Errors = gqlErrs
+		for key, value := range payload.Extensions {
+			if response.Extensions == nil {
+				response.Extensions = map[string]interface{}{}
+			}
+			response.Extensions[key] = value
+		}
+
+		if err := decodeData(data, responseOut); err != nil {
+			return err
+		}
+
+		observer(IncrementalResult{
+			Initial:   initial,
+			Completed: !payload.HasNext,
+			Errors:    gqlErrs,
+		})
+		initial = false
+
+		if !payload.HasNext {
+			break
+		}
+	}
+
+	if len(gqlErrs) > 0 {
+		return gqlErrs
+	}
+
+	return nil
+}
+
+// decodeData decodes data accumulated from incremental payloads to responseOut
+func decodeData(data interface{}, responseOut interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	if err := json.NewDecoder(bytes.NewReader(encoded)).Decode(responseOut); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return nil
+}
+
+// apply merges deferred data into the object at the path, or inserts streamed items into the list
+// starting at the index specified by the last element of the path
+func (p incrementalPatch) apply(data interface{}) (interface{}, error) {
+	if p.Items != nil {
+		if len(p.Path) == 0 {
+			return nil, fmt.Errorf("path of streamed items is empty")
+		}
+
+		index, ok := pathIndex(p.Path[len(p.Path)-1])
+		if !ok {
+			return nil, fmt.Errorf("path of streamed items does not end with index: %v", p.Path)
+		}
+
+		return updateAtPath(data, p.Path[:len(p.Path)-1], func(value interface{}) (interface{}, error) {
+			list, ok := value.([]interface{})
+			if !ok || index > len(list) {
+				return nil, fmt.Errorf("failed to stream items at %v: list not found", p.Path)
+			}
+			return append(list[:index], p.Items...), nil
+		})
+	}
+
+	return updateAtPath(data, p.Path, func(value interface{}) (interface{}, error) {
+		return mergeData(value, p.Data), nil
+	})
+}
+
+// updateAtPath replaces the value at the path with the updated one
+func updateAtPath(value interface{}, path []interface{}, update func(interface{}) (interface{}, error)) (interface{}, error) {
+	if len(path) == 0 {
+		return update(value)
+	}
+
+	if key, ok := path[0].(string); ok {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("object with %q field not found", key)
+		}
+
+		updated, err := updateAtPath(object[key], path[1:], update)
+		if err != nil {
+			return nil, err
+		}
+		object[key] = updated
+
+		return object, nil
+	}
+
+	index, ok := pathIndex(path[0])
+	if !ok {
+		return nil, fmt.Errorf("invalid path element: %v", path[0])
+	}
+
+	list, ok := value.([]interface{})
+	if !ok || index >= len(list) {
+		return nil, fmt.Errorf("list with element %d not found", index)
+	}
+
+	updated, err := updateAtPath(list[index], path[1:], update)
+	if err != nil {
+		return nil, err
+	}
+	list[index] = updated
+
+	return list, nil
+}
+
+// mergeData merges fields of the source object into the destination recursively
+func mergeData(destination, source interface{}) interface{} {
+	destinationObject, ok := destination.(map[string]interface{})
+	if !ok {
+		return source
+	}
+	sourceObject, ok := source.(map[string]interface{})
+	if !ok {
+		return source
+	}
+
+	for key, value := range sourceObject {
+		destinationObject[key] = mergeData(destinationObject[key], value)
+	}
+
+	return destinationObject
+}
+
+func pathIndex(element interface{}) (int, bool) {
+	switch index := element.(type) {
+	case json.Number:
+		i, err := strconv.Atoi(index.String())
+		return i, err == nil && i >= 0
+	case float64:
+		return int(index), index >= 0
+	}
+
+	return 0, false
+}
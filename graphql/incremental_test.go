@@ -0,0 +1,76 @@
+package graphql
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncrementalPatch_Apply(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		data        string
+		patch       string
+		expected    string
+		err         string
+	}{
+		{
+			description: "should merge deferred data",
+			data:        `{"human":{"id":"1","dogs":[{"id":"2"}]}}`,
+			patch:       `{"data":{"name":"Ted","dogs":[{"id":"2"}]},"path":["human"]}`,
+			expected:    `{"human":{"id":"1","name":"Ted","dogs":[{"id":"2"}]}}`,
+		},
+		{
+			description: "should merge deferred data into list element",
+			data:        `{"humans":[{"id":"1"},{"id":"2","dog":{"id":"3"}}]}`,
+			patch:       `{"data":{"dog":{"name":"Rex"}},"path":["humans",1]}`,
+			expected:    `{"humans":[{"id":"1"},{"id":"2","dog":{"id":"3","name":"Rex"}}]}`,
+		},
+		{
+			description: "should append streamed items",
+			data:        `{"human":{"dogs":[{"id":"1"}]}}`,
+			patch:       `{"items":[{"id":"2"},{"id":"3"}],"path":["human","dogs",1]}`,
+			expected:    `{"human":{"dogs":[{"id":"1"},{"id":"2"},{"id":"3"}]}}`,
+		},
+		{
+			description: "should return error if path does not exist",
+			data:        `{"human":null}`,
+			patch:       `{"data":{"name":"Ted"},"path":["human","dogs",0]}`,
+			err:         "object with \"dogs\" field not found",
+		},
+		{
+			description: "should return error if streamed items path does not end with index",
+			data:        `{"human":{"dogs":[]}}`,
+			patch:       `{"items":[{"id":"1"}],"path":["human","dogs"]}`,
+			err:         "does not end with index",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			var data interface{}
+			decodeWithNumbers(t, testCase.data, &data)
+			var patch incrementalPatch
+			decodeWithNumbers(t, testCase.patch, &patch)
+
+			result, err := patch.apply(data)
+			if testCase.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.err)
+				return
+			}
+			require.NoError(t, err)
+
+			encoded, err := json.Marshal(result)
+			require.NoError(t, err)
+			assert.JSONEq(t, testCase.expected, string(encoded))
+		})
+	}
+}
+
+func decodeWithNumbers(t *testing.T, data string, out interface{}) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(out))
+}
@@ -1,10 +1,13 @@
 package graphql
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
-	"strings"
 )
 
 // TODO: consider making it as a parser struct not as a function - can have both query parser and input parser
@@ -13,6 +16,12 @@ type ParserOptions struct {
 	// SkipZeroValues determines if parameters with zero values should be skipped when parsing to input
 	// Be aware that values like 'false' for the bool field or "" for a string field are also zero values
 	SkipZeroValues bool
+	// Compact determines if generated documents should be printed in a single line without redundant whitespaces
+	// Use PrettyPrint to format compact documents for logging
+	Compact bool
+	// IncludeTypeName determines if __typename field should be added to each selection set of the query
+	// Embed TypeName in the requested types to receive its value
+	IncludeTypeName bool
 }
 
 func ParseToGQLInput(input OperationInput, options ...ParserOptions) (string, error) {
@@ -26,33 +35,34 @@ func ParseToGQLInput(input OperationInput, options ...ParserOptions) (string, er
 		opts = options[0]
 	}
 
-	return opts.parseToGQLInput(input)
+	return opts.parseToGQLInput(input, 0)
 }
 
-func (o ParserOptions) parseToGQLInput(input OperationInput) (string, error) {
-	gqlInput := ""
+func (o ParserOptions) printer() printer {
+	return printer{compact: o.Compact}
+}
+
+func (o ParserOptions) parseToGQLInput(input OperationInput, indent int) (string, error) {
 	sortedKeys := input.sortedKeys()
+	params := make([]string, 0, len(sortedKeys))
 
 	for _, paramName := range sortedKeys {
 		value := input[paramName]
 
-		inputValue, ok, err := o.objectToGQLInput(value, 0)
+		inputValue, ok, err := o.valueToGQLInput(reflect.ValueOf(value), indent)
 		if err != nil {
-			return "", fmt.Errorf("failed to parse to GQL input, %w", err)
+			return "", fmt.Errorf("failed to parse to GQL input, invalid input for %s parameter: %w", paramName, err)
 		}
 		if !ok {
 			return "", fmt.Errorf("failed to parse to GQL input, invalid input for %s parameter", paramName)
 		}
-		gqlInput = fmt.Sprintf("%s %s: %s,", gqlInput, paramName, inputValue)
+		params = append(params, o.printer().keyValue(paramName, inputValue))
 	}
 
-	gqlInput = strings.TrimPrefix(gqlInput, " ")
-	return strings.TrimSuffix(gqlInput, ","), nil
+	return o.printer().arguments(params), nil
 }
 
-func (o ParserOptions) objectToGQLInput(object interface{}, indent int) (string, bool, error) {
-	reflectVal := reflect.ValueOf(object)
-
+func (o ParserOptions) valueToGQLInput(reflectVal reflect.Value, indent int) (string, bool, error) {
 	if reflectVal.Kind() == reflect.Invalid {
 		return "", false, nil
 	}
@@ -63,10 +73,16 @@ func (o ParserOptions) objectToGQLInput(object interface{}, indent int) (string,
 
 	switch reflectVal.Kind() {
 	case reflect.Struct:
+		if isMarshaledStruct(reflectVal.Type()) {
+			return marshaledToGQLInput(reflectVal)
+		}
 		return o.structToGQLInput(reflectVal, indent)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		value := strconv.FormatInt(reflectVal.Int(), 10)
 		return value, true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value := strconv.FormatUint(reflectVal.Uint(), 10)
+		return value, true, nil
 	case reflect.String:
 		return o.stringToGQLInput(reflectVal), true, nil
 	case reflect.Float32:
@@ -82,72 +98,117 @@ func (o ParserOptions) objectToGQLInput(object interface{}, indent int) (string,
 		if !reflectValElem.IsValid() {
 			return "", false, nil
 		}
-		return o.objectToGQLInput(reflectValElem.Interface(), indent)
+		return o.valueToGQLInput(reflectValElem, indent)
 	case reflect.Array, reflect.Slice:
 		return o.arrayToGQLInput(reflectVal, indent)
 	case reflect.Map:
 		return o.mapToGQLInput(reflectVal, indent)
 	}
 
-	// TODO - other cases (if need to support more?)
-
-	return "", false, nil
+	return "", false, fmt.Errorf("unsupported type %s", reflectVal.Type())
 }
 
 func (o ParserOptions) stringToGQLInput(reflectVal reflect.Value) string {
 	return fmt.Sprintf("\"%s\"", reflectVal.String())
 }
 
+// marshaledToGQLInput converts the value marshaling itself to JSON or text to the scalar input, preferring JSON as encoding/json does.
+// Values marshaled to JSON objects or arrays are not supported, as their JSON representation is not valid GraphQL input.
+func marshaledToGQLInput(reflectVal reflect.Value) (string, bool, error) {
+	// Value is copied to be addressable, so that marshalers implemented with pointer receivers are used
+	pointer := reflect.New(reflectVal.Type())
+	pointer.Elem().Set(reflectVal)
+
+	switch marshaler := pointer.Interface().(type) {
+	case json.Marshaler:
+		encoded, err := marshaler.MarshalJSON()
+		if err != nil {
+			return "", false, fmt.Errorf("failed to marshal %s to JSON: %w", reflectVal.Type(), err)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(encoded))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return "", false, fmt.Errorf("failed to decode JSON of %s: %w", reflectVal.Type(), err)
+		}
+
+		switch value.(type) {
+		case nil:
+			return "", false, nil
+		case string, json.Number, bool:
+			return string(bytes.TrimSpace(encoded)), true, nil
+		}
+		return "", false, fmt.Errorf("unsupported type %s, it is marshaled to JSON which is not a scalar", reflectVal.Type())
+	case encoding.TextMarshaler:
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return "", false, fmt.Errorf("failed to marshal %s to text: %w", reflectVal.Type(), err)
+		}
+
+		quoted, err := json.Marshal(string(text))
+		if err != nil {
+			return "", false, fmt.Errorf("failed to quote text of %s: %w", reflectVal.Type(), err)
+		}
+		return string(quoted), true, nil
+	}
+
+	return "", false, fmt.Errorf("unsupported type %s", reflectVal.Type())
+}
+
 func (o ParserOptions) structToGQLInput(reflectVal reflect.Value, indent int) (string, bool, error) {
-	fieldsString := ""
+	typeFields := structFields(reflectVal.Type())
+	if len(typeFields) == 0 {
+		return "", false, fmt.Errorf("unsupported type %s, it has no exported fields", reflectVal.Type())
+	}
+	fields := make([]string, 0, len(typeFields))
+
+	for _, typeField := range typeFields {
+		// Names starting with "__" are reserved for introspection and cannot be used in input objects
+		if typeField.name == typeNameField {
+			continue
+		}
 
-	for i := 0; i < reflectVal.NumField(); i++ {
-		inputName := reflectVal.Type().Field(i).Tag.Get(jsonTagKey)
-		if inputName == "" {
-			inputName = reflectVal.Type().Field(i).Name
+		fieldVal, ok := fieldByIndex(reflectVal, typeField.index)
+		if !ok {
+			continue
 		}
 
-		inputValue, ok, err := o.objectToGQLInput(reflectVal.Field(i).Interface(), indent+1)
+		inputValue, ok, err := o.valueToGQLInput(fieldVal, indent+1)
 		if err != nil {
-			return "", false, err
+			return "", false, fmt.Errorf("failed to parse %s field: %w", typeField.name, err)
 		}
 		if ok {
-			fieldsString = fmt.Sprintf("%s\n%s%s: %s", fieldsString, tabsIndent(indent+1), inputName, inputValue)
+			fields = append(fields, o.printer().keyValue(typeField.name, inputValue))
 		}
 	}
 
-	if fieldsString == "" {
+	if len(fields) == 0 {
 		return "", false, nil
 	}
 
-	fieldsString = "{" + fieldsString + "\n" + tabsIndent(indent) + "}"
-
-	return fieldsString, true, nil
+	return o.printer().block("{", "}", fields, "", indent), true, nil
 }
 
 func (o ParserOptions) arrayToGQLInput(reflectVal reflect.Value, indent int) (string, bool, error) {
-	if reflectVal.IsNil() {
+	if reflectVal.Kind() == reflect.Slice && reflectVal.IsNil() {
 		return "", false, nil
 	}
 
-	elementsString := "["
+	elements := make([]string, 0, reflectVal.Len())
 	for i := 0; i < reflectVal.Len(); i++ {
 		arrayElem := reflectVal.Index(i)
 
-		inputValue, ok, err := o.objectToGQLInput(arrayElem.Interface(), indent+1)
+		inputValue, ok, err := o.valueToGQLInput(arrayElem, indent+1)
 		if err != nil {
 			return "", false, err
 		}
 		if ok {
-			elem := fmt.Sprintf("\t%s", inputValue)
-			elementsString = fmt.Sprintf("%s\n%s%s,", elementsString, tabsIndent(indent), elem)
+			elements = append(elements, inputValue)
 		}
 	}
 
-	elementsString = strings.TrimSuffix(elementsString, ",")
-	elementsString += "\n" + tabsIndent(indent) + "]"
-
-	return elementsString, true, nil
+	return o.printer().block("[", "]", elements, ",", indent), true, nil
 }
 
 func (o ParserOptions) mapToGQLInput(reflectVal reflect.Value, indent int) (string, bool, error) {
@@ -155,26 +216,79 @@ func (o ParserOptions) mapToGQLInput(reflectVal reflect.Value, indent int) (stri
 		return "", false, nil
 	}
 
-	mapElemsString := "{"
+	keys, err := sortedMapKeys(reflectVal)
+	if err != nil {
+		return "", false, err
+	}
+
+	elements := make([]string, 0, len(keys))
 
-	mapIter := reflectVal.MapRange()
-	for mapIter.Next() {
-		key := mapIter.Key()
-		if key.Kind() != reflect.String {
-			return "", false, fmt.Errorf("unsupported map key type %s, must be of kind string", key.Kind())
+	for _, key := range keys {
+		value, ok, err := o.valueToGQLInput(reflectVal.MapIndex(key.value), indent+1)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to parse value of %s key: %w", key.name, err)
 		}
+		if ok {
+			elements = append(elements, o.printer().keyValue(key.name, value))
+		}
+	}
+
+	// Map is skipped only if all its elements were skipped, empty map is a valid empty input object
+	if len(elements) == 0 && len(keys) > 0 {
+		return "", false, nil
+	}
+
+	return o.printer().block("{", "}", elements, "", indent), true, nil
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+type mapKey struct {
+	name  string
+	value reflect.Value
+}
 
-		value, ok, err := o.objectToGQLInput(mapIter.Value().Interface(), indent+1)
+// sortedMapKeys resolves names of map keys and sorts them so that the output does not depend on map iteration order.
+// Similarly to encoding/json keys of string kind are used directly, other key types need to implement encoding.TextMarshaler
+func sortedMapKeys(reflectVal reflect.Value) ([]mapKey, error) {
+	keys := make([]mapKey, 0, reflectVal.Len())
+
+	for _, key := range reflectVal.MapKeys() {
+		name, err := resolveMapKeyName(key)
 		if err != nil {
-			return "", false, err
-		} else if !ok {
-			return "", false, nil
+			return nil, err
 		}
+		keys = append(keys, mapKey{name: name, value: key})
+	}
 
-		elem := fmt.Sprintf("\t%s: %s", key, value)
-		mapElemsString = fmt.Sprintf("%s\n%s%s", mapElemsString, tabsIndent(indent), elem)
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].name < keys[j].name
+	})
+
+	return keys, nil
+}
+
+func resolveMapKeyName(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+
+	if key.Type().Implements(textMarshalerType) {
+		if !key.CanInterface() {
+			return "", fmt.Errorf("unsupported map key type %s, cannot access value of unexported field", key.Type())
+		}
+		if key.Kind() == reflect.Ptr && key.IsNil() {
+			return "", fmt.Errorf("unsupported nil map key of type %s", key.Type())
+		}
+
+		textMarshaler := key.Interface().(encoding.TextMarshaler)
+
+		text, err := textMarshaler.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal map key of type %s: %w", key.Type(), err)
+		}
+		return string(text), nil
 	}
-	mapElemsString += "\n" + tabsIndent(indent) + "}"
 
-	return mapElemsString, true, nil
+	return "", fmt.Errorf("unsupported map key type %s, must be of kind string or implement encoding.TextMarshaler", key.Type())
 }
@@ -3,12 +3,23 @@ package graphql
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/szymongib/graphql-client/util"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+type textKey struct {
+	prefix string
+	id     int
+}
+
+func (k textKey) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%s-%d", k.prefix, k.id)), nil
+}
+
 func TestParseToGQLInput(t *testing.T) {
 
 	for _, testCase := range []struct {
@@ -257,6 +268,158 @@ func TestParseToGQLInput(t *testing.T) {
 	MapAlias: {
 		k1: "test"
 	}
+}`,
+		},
+		{
+			description: "embedded structs",
+			input: OperationInput{
+				"in": overridingStruct{
+					embeddingStruct: embeddingStruct{
+						namedStruct: namedStruct{Name: "name", Kind: "embedded"},
+						ID:          "id",
+					},
+					Kind: "kind",
+					Skip: "skip",
+				},
+			},
+			expectedInput: `in: {
+	name: "name"
+	id: "id"
+	kind: "kind"
+	extra: {
+		stringField: ""
+		intField: 0
+	}
+}`,
+		},
+		{
+			description: "embedded structs with tags",
+			input: OperationInput{
+				"in": taggedEmbeddingStruct{
+					untaggedTitleStruct: untaggedTitleStruct{Title: "untagged"},
+					taggedTitleStruct:   taggedTitleStruct{Heading: "tagged"},
+					Named:               Named{Name: "named"},
+				},
+			},
+			expectedInput: `in: {
+	Title: "tagged"
+	named: {
+		name: "named"
+	}
+}`,
+		},
+		{
+			description: "unexported fields",
+			input: OperationInput{
+				"in": unexportedFieldsStruct{
+					Name:            "name",
+					internal:        "internal",
+					namedStruct:     namedStruct{Name: "named", Kind: "named-kind"},
+					describedStruct: &describedStruct{Description: "description"},
+				},
+			},
+			expectedInput: `in: {
+	name: "name"
+	named: {
+		name: "named"
+		kind: "named-kind"
+	}
+	description: "description"
+	kind: ""
+}`,
+		},
+		{
+			description: "unsigned integers and arrays",
+			input: OperationInput{
+				"in": struct {
+					Count uint64
+					Ids   [2]uint8
+				}{Count: 18446744073709551615, Ids: [2]uint8{1, 2}},
+			},
+			expectedInput: `in: {
+	Count: 18446744073709551615
+	Ids: [
+		1,
+		2
+	]
+}`,
+		},
+		{
+			description: "type name",
+			input: OperationInput{
+				"in": typeNamedStruct{
+					TypeName: TypeName{Typename: "Type"},
+					Name:     "name",
+				},
+			},
+			expectedInput: `in: {
+	name: "name"
+	inner: {
+		stringField: ""
+		intField: 0
+	}
+}`,
+		},
+		{
+			description: "maps with multiple keys in sorted order",
+			input: OperationInput{
+				"in": map[string]interface{}{
+					"c": 3,
+					"a": "first",
+					"b": []int{2},
+				},
+			},
+			expectedInput: `in: {
+	a: "first"
+	b: [
+		2
+	]
+	c: 3
+}`,
+		},
+		{
+			description: "maps with text marshaler keys",
+			input: OperationInput{
+				"in": map[textKey]string{
+					{prefix: "k", id: 2}: "second",
+					{prefix: "k", id: 1}: "first",
+				},
+			},
+			expectedInput: `in: {
+	k-1: "first"
+	k-2: "second"
+}`,
+		},
+		{
+			description: "maps with invalid values",
+			input: OperationInput{
+				"in": map[string]*simpleStruct{
+					"k1": nil,
+					"k2": {StringField: "test"},
+				},
+			},
+			expectedInput: `in: {
+	k2: {
+		StringField: "test"
+		IntField: 0
+		BoolField: false
+	}
+}`,
+		},
+		{
+			description: "empty map",
+			input: OperationInput{
+				"in": map[string]string{},
+			},
+			expectedInput: `in: {}`,
+		},
+		{
+			description: "map with nested empty map",
+			input: OperationInput{
+				"in": map[string]interface{}{"filter": map[string]int{}},
+			},
+			expectedInput: `in: {
+	filter: {}
 }`,
 		},
 	} {
@@ -319,6 +482,20 @@ func TestParseToGQLInput(t *testing.T) {
 		})
 	}
 
+	t.Run("compact multiple input params", func(t *testing.T) {
+		gqlInput, err := ParseToGQLInput(OperationInput{
+			"in": slicesOfPointersStruct{
+				Name:             "test",
+				SimpleStructPtrs: []*simpleStruct{{StringField: "first", BoolField: true}},
+			},
+			"ids": []string{"a", "b"},
+			"map": map[string]int{"k1": 1, "k2": 2},
+		}, ParserOptions{Compact: true, SkipZeroValues: true})
+		assert.NoError(t, err)
+
+		assert.Equal(t, `ids:["a","b"],in:{name:"test" simpleStructPtrs:[{StringField:"first" BoolField:true}]},map:{k1:1 k2:2}`, gqlInput)
+	})
+
 	t.Run("should return error on nil input", func(t *testing.T) {
 		gqlInput, err := ParseToGQLInput(OperationInput{"test": "test", "object": nil})
 		assert.Error(t, err)
@@ -345,4 +522,60 @@ func TestParseToGQLInput(t *testing.T) {
 		assert.Empty(t, gqlInput)
 	})
 
+	t.Run("should parse marshaled structs as scalars", func(t *testing.T) {
+		createdAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		gqlInput, err := ParseToGQLInput(OperationInput{"in": event{Name: "a", CreatedAt: createdAt, Amount: money{cents: 1050}}})
+		require.NoError(t, err)
+		assert.Equal(t, `in: {
+	name: "a"
+	createdAt: "2020-01-02T03:04:05Z"
+	amount: 10.50
+}`, gqlInput)
+
+		gqlInput, err = ParseToGQLInput(OperationInput{"key": textKey{prefix: "dog", id: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, `key: "dog-1"`, gqlInput)
+	})
+
+	t.Run("should return error if input contains struct without exported fields", func(t *testing.T) {
+		gqlInput, err := ParseToGQLInput(OperationInput{"in": struct {
+			Name  string
+			Inner noExportedFieldsStruct
+		}{Name: "a", Inner: noExportedFieldsStruct{internal: "b"}}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse Inner field: unsupported type graphql.noExportedFieldsStruct, it has no exported fields")
+		assert.Empty(t, gqlInput)
+	})
+
+	t.Run("should return error if input contains unsupported kind", func(t *testing.T) {
+		gqlInput, err := ParseToGQLInput(OperationInput{"in": unsupportedTypesStruct{Name: "test", Callback: func() {}}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid input for in parameter: failed to parse Callback field: unsupported type func()")
+		assert.Empty(t, gqlInput)
+
+		gqlInput, err = ParseToGQLInput(OperationInput{"in": struct{ Events []chan string }{Events: []chan string{make(chan string)}}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported type chan string")
+		assert.Empty(t, gqlInput)
+
+		gqlInput, err = ParseToGQLInput(OperationInput{"in": map[string]interface{}{"value": complex(1, 2)}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse value of value key: unsupported type complex128")
+		assert.Empty(t, gqlInput)
+	})
+
+	t.Run("should produce the same output for maps regardless of iteration order", func(t *testing.T) {
+		input := OperationInput{"in": map[string]int{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6}}
+
+		expectedInput, err := ParseToGQLInput(input)
+		assert.NoError(t, err)
+
+		for i := 0; i < 20; i++ {
+			gqlInput, err := ParseToGQLInput(input)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedInput, gqlInput)
+		}
+	})
+
 }
@@ -0,0 +1,174 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// LogLevel is the severity of the log entry
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	}
+
+	return "unknown"
+}
+
+// Keys of the fields of log entries
+const (
+	LogFieldOperationName = "operation_name"
+	LogFieldOperationType = "operation_type"
+	LogFieldQuery         = "query"
+	LogFieldHeaders       = "headers"
+	LogFieldVariables     = "variables"
+	LogFieldStatus        = "status"
+	LogFieldDuration      = "duration"
+	LogFieldErrorCount    = "error_count"
+	LogFieldError         = "error"
+	LogFieldBody          = "body"
+	LogFieldBodySize      = "body_size"
+	LogFieldBatchSize     = "batch_size"
+	LogFieldAttempt       = "attempt"
+	LogFieldBackoff       = "backoff"
+	LogFieldCircuitFrom   = "circuit_from"
+	LogFieldCircuitTo     = "circuit_to"
+	LogFieldEndpoint      = "endpoint"
+)
+
+// LogField is the key-value pair providing context of the log entry
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger logs entries with levels and fields.
+// Adapters for log/slog and logrus are available in the logging package.
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, message string, fields ...LogField)
+}
+
+// LoggerFunc is an adapter allowing to use ordinary function as Logger
+type LoggerFunc func(ctx context.Context, level LogLevel, message string, fields ...LogField)
+
+func (f LoggerFunc) Log(ctx context.Context, level LogLevel, message string, fields ...LogField) {
+	f(ctx, level, message, fields...)
+}
+
+func (c Client) log(ctx context.Context, level LogLevel, message string, fields ...LogField) {
+	if c.options.logger != nil {
+		c.options.logger.Log(ctx, level, message, fields...)
+	}
+}
+
+func (c Client) logRequest(ctx context.Context, request Request) {
+	if c.options.logger == nil {
+		return
+	}
+
+	redaction := c.options.redaction
+	c.log(ctx, LogLevelDebug, "Executing request",
+		LogField{Key: LogFieldOperationType, Value: string(request.OperationType())},
+		LogField{Key: LogFieldOperationName, Value: request.ResolvedOperationName()},
+		LogField{Key: LogFieldHeaders, Value: redaction.redactHeaders(request.Header)},
+		LogField{Key: LogFieldQuery, Value: PrettyPrint(redaction.redactQuery(request.Query))},
+		LogField{Key: LogFieldVariables, Value: redaction.redactVariables(request.Variables)},
+	)
+}
+
+// logResult logs the outcome of the request with its duration, response status and the number of GraphQL errors
+func (c Client) logResult(ctx context.Context, request Request, response *Response, duration time.Duration, err error) {
+	if c.options.logger == nil {
+		return
+	}
+
+	var gqlErrs GraphQLErrors
+	errors.As(err, &gqlErrs)
+
+	fields := []LogField{
+		{Key: LogFieldOperationType, Value: string(request.OperationType())},
+		{Key: LogFieldOperationName, Value: request.ResolvedOperationName()},
+		{Key: LogFieldDuration, Value: duration},
+		{Key: LogFieldErrorCount, Value: len(gqlErrs)},
+	}
+	if response != nil {
+		fields = append(fields, LogField{Key: LogFieldStatus, Value: response.StatusCode})
+	}
+
+	if err != nil {
+		c.log(ctx, LogLevelError, "Request failed", append(fields, LogField{Key: LogFieldError, Value: c.options.redaction.truncate(err.Error())})...)
+		return
+	}
+
+	c.log(ctx, LogLevelInfo, "Request executed", fields...)
+}
+
+// capturingBody captures the response body while it is read by the decoder, up to the limit
+type capturingBody struct {
+	io.ReadCloser
+	limit    int
+	captured bytes.Buffer
+	size     int64
+}
+
+func (b *capturingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+
+	capture := n
+	if b.limit > 0 && b.captured.Len()+capture > b.limit {
+		capture = b.limit - b.captured.Len()
+	}
+	b.captured.Write(p[:capture])
+
+	return n, err
+}
+
+// captureBody replaces the body of the response to capture it for logging without changing the way it is decoded.
+// Only the part of the body that is logged is kept in memory.
+func (c Client) captureBody(response *http.Response) *capturingBody {
+	if c.options.logger == nil {
+		return nil
+	}
+
+	body := &capturingBody{ReadCloser: response.Body}
+	if maxLength := c.options.redaction.MaxBodyLength; maxLength > 0 {
+		// One more byte is captured to determine if the body needs to be truncated
+		body.limit = maxLength + 1
+	}
+	response.Body = body
+
+	return body
+}
+
+// logResponse logs the part of the response body read during decoding
+func (c Client) logResponse(ctx context.Context, response *http.Response, body *capturingBody) {
+	if body == nil {
+		return
+	}
+
+	c.log(ctx, LogLevelDebug, "Received response",
+		LogField{Key: LogFieldStatus, Value: response.StatusCode},
+		LogField{Key: LogFieldBody, Value: c.options.redaction.truncate(body.captured.String())},
+		LogField{Key: LogFieldBodySize, Value: body.size},
+	)
+}
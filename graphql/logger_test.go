@@ -0,0 +1,37 @@
+package graphql
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapturingBody(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		limit       int
+		captured    string
+	}{
+		{description: "should capture body up to the limit", limit: 5, captured: "01234"},
+		{description: "should capture whole body without limit", limit: 0, captured: "0123456789"},
+		{description: "should capture whole body shorter than limit", limit: 20, captured: "0123456789"},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			body := &capturingBody{
+				ReadCloser: ioutil.NopCloser(iotest.HalfReader(strings.NewReader("0123456789"))),
+				limit:      testCase.limit,
+			}
+
+			read, err := ioutil.ReadAll(body)
+			require.NoError(t, err)
+
+			assert.Equal(t, "0123456789", string(read))
+			assert.Equal(t, testCase.captured, body.captured.String())
+			assert.Equal(t, int64(10), body.size)
+		})
+	}
+}
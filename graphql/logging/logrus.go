@@ -0,0 +1,33 @@
+// Package logging provides adapters of popular loggers to graphql.Logger
+package logging
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+// Logrus returns graphql.Logger logging entries with the logrus logger, passing the fields as logrus fields
+func Logrus(logger logrus.FieldLogger) graphql.Logger {
+	return graphql.LoggerFunc(func(ctx context.Context, level graphql.LogLevel, message string, fields ...graphql.LogField) {
+		logrusFields := make(logrus.Fields, len(fields))
+		for _, field := range fields {
+			logrusFields[field.Key] = field.Value
+		}
+
+		entry := logger.WithFields(logrusFields).WithContext(ctx)
+
+		switch level {
+		case graphql.LogLevelDebug:
+			entry.Debug(message)
+		case graphql.LogLevelInfo:
+			entry.Info(message)
+		case graphql.LogLevelWarn:
+			entry.Warn(message)
+		default:
+			entry.Error(message)
+		}
+	})
+}
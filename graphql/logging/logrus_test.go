@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func TestLogrus(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	for _, testCase := range []struct {
+		level         graphql.LogLevel
+		expectedLevel logrus.Level
+	}{
+		{level: graphql.LogLevelDebug, expectedLevel: logrus.DebugLevel},
+		{level: graphql.LogLevelInfo, expectedLevel: logrus.InfoLevel},
+		{level: graphql.LogLevelWarn, expectedLevel: logrus.WarnLevel},
+		{level: graphql.LogLevelError, expectedLevel: logrus.ErrorLevel},
+	} {
+		t.Run(testCase.level.String(), func(t *testing.T) {
+			hook.Reset()
+
+			Logrus(logger).Log(context.Background(), testCase.level, "Request executed",
+				graphql.LogField{Key: graphql.LogFieldOperationName, Value: "Dogs"},
+				graphql.LogField{Key: graphql.LogFieldStatus, Value: 200},
+			)
+
+			entry := hook.LastEntry()
+			require.NotNil(t, entry)
+			assert.Equal(t, testCase.expectedLevel, entry.Level)
+			assert.Equal(t, "Request executed", entry.Message)
+			assert.Equal(t, logrus.Fields{"operation_name": "Dogs", "status": 200}, entry.Data)
+		})
+	}
+}
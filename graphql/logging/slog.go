@@ -0,0 +1,36 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+// Slog returns graphql.Logger logging entries with the slog logger, passing the fields as attributes
+func Slog(logger *slog.Logger) graphql.Logger {
+	return graphql.LoggerFunc(func(ctx context.Context, level graphql.LogLevel, message string, fields ...graphql.LogField) {
+		attrs := make([]slog.Attr, 0, len(fields))
+		for _, field := range fields {
+			attrs = append(attrs, slog.Any(field.Key, field.Value))
+		}
+
+		logger.LogAttrs(ctx, slogLevel(level), message, attrs...)
+	})
+}
+
+func slogLevel(level graphql.LogLevel) slog.Level {
+	switch level {
+	case graphql.LogLevelDebug:
+		return slog.LevelDebug
+	case graphql.LogLevelInfo:
+		return slog.LevelInfo
+	case graphql.LogLevelWarn:
+		return slog.LevelWarn
+	}
+
+	return slog.LevelError
+}
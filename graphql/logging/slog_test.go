@@ -0,0 +1,46 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func TestSlog(t *testing.T) {
+	for _, testCase := range []struct {
+		level         graphql.LogLevel
+		expectedLevel string
+	}{
+		{level: graphql.LogLevelDebug, expectedLevel: "DEBUG"},
+		{level: graphql.LogLevelInfo, expectedLevel: "INFO"},
+		{level: graphql.LogLevelWarn, expectedLevel: "WARN"},
+		{level: graphql.LogLevelError, expectedLevel: "ERROR"},
+	} {
+		t.Run(testCase.level.String(), func(t *testing.T) {
+			var output bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			Slog(logger).Log(context.Background(), testCase.level, "Request executed",
+				graphql.LogField{Key: graphql.LogFieldOperationName, Value: "Dogs"},
+				graphql.LogField{Key: graphql.LogFieldStatus, Value: 200},
+			)
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(output.Bytes(), &entry))
+			assert.Equal(t, testCase.expectedLevel, entry["level"])
+			assert.Equal(t, "Request executed", entry["msg"])
+			assert.Equal(t, "Dogs", entry["operation_name"])
+			assert.Equal(t, float64(200), entry["status"])
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// OperationMetrics are measurements of the executed operation reported to Metrics
+type OperationMetrics struct {
+	OperationType OperationType
+	OperationName string
+	// Duration is the time of the execution of the operation, including retries
+	Duration time.Duration
+	// StatusCode is the status of the last HTTP response, it is 0 if no response was received
+	StatusCode int
+	// Errors are GraphQL errors returned by the server
+	Errors GraphQLErrors
+	// Retries is the number of times the operation was retried according to the retry policy
+	Retries int
+	// RequestSize is the number of bytes of request bodies sent for the operation, after compression.
+	// Sizes are not measured for operations sent in batches.
+	RequestSize int64
+	// ResponseSize is the number of bytes of response bodies read for the operation, before decompression.
+	// Sizes are not measured for operations sent in batches.
+	ResponseSize int64
+	// Err is the error returned for the operation, nil if it succeeded
+	Err error
+}
+
+// Metrics receives measurements of executed operations.
+// Adapter for Prometheus is available in the metrics package.
+type Metrics interface {
+	ObserveOperation(ctx context.Context, metrics OperationMetrics)
+}
+
+// MetricsFunc is an adapter allowing to use ordinary function as Metrics
+type MetricsFunc func(ctx context.Context, metrics OperationMetrics)
+
+func (f MetricsFunc) ObserveOperation(ctx context.Context, metrics OperationMetrics) {
+	f(ctx, metrics)
+}
+
+type metricsRecorderKey struct{}
+
+// metricsRecorder collects measurements from the stages of the request pipeline executing the operation
+type metricsRecorder struct {
+	retries      int
+	requestSize  int64
+	responseSize int64
+}
+
+func withMetricsRecorder(ctx context.Context) (context.Context, *metricsRecorder) {
+	recorder := &metricsRecorder{}
+	return context.WithValue(ctx, metricsRecorderKey{}, recorder), recorder
+}
+
+// recorderFromContext returns the recorder of the operation or nil if metrics are not enabled
+func recorderFromContext(ctx context.Context) *metricsRecorder {
+	recorder, _ := ctx.Value(metricsRecorderKey{}).(*metricsRecorder)
+	return recorder
+}
+
+func (r *metricsRecorder) retry() {
+	if r != nil {
+		r.retries++
+	}
+}
+
+// recordRequest records the size of the request body and replaces the response body to count bytes read from it
+func (r *metricsRecorder) recordRequest(httpRequest *http.Request, res *http.Response) {
+	if r == nil {
+		return
+	}
+
+	if httpRequest.ContentLength > 0 {
+		r.requestSize += httpRequest.ContentLength
+	}
+	res.Body = &countingBody{ReadCloser: res.Body, size: &r.responseSize}
+}
+
+// countingBody counts bytes read from the response body
+type countingBody struct {
+	io.ReadCloser
+	size *int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	*b.size += int64(n)
+
+	return n, err
+}
+
+// observeOperation reports measurements of the executed operation
+func (c Client) observeOperation(ctx context.Context, request Request, recorder *metricsRecorder, response *Response, duration time.Duration, err error) {
+	metrics := OperationMetrics{
+		OperationType: request.OperationType(),
+		OperationName: request.ResolvedOperationName(),
+		Duration:      duration,
+		Retries:       recorder.retries,
+		RequestSize:   recorder.requestSize,
+		ResponseSize:  recorder.responseSize,
+		Err:           err,
+	}
+
+	if response != nil {
+		metrics.StatusCode = response.StatusCode
+	}
+	var httpErr *HTTPError
+	if metrics.StatusCode == 0 && errors.As(err, &httpErr) {
+		metrics.StatusCode = httpErr.StatusCode
+	}
+	errors.As(err, &metrics.Errors)
+
+	c.options.metrics.ObserveOperation(ctx, metrics)
+}
@@ -0,0 +1,106 @@
+// Package metrics provides adapters of metrics systems to graphql.Metrics
+package metrics
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+const namespace = "graphql_client"
+
+// Labels of the metrics
+const (
+	OperationTypeLabel = "operation_type"
+	OperationNameLabel = "operation_name"
+	StatusCodeLabel    = "status_code"
+	ErrorCodeLabel     = "code"
+)
+
+var operationLabels = []string{OperationTypeLabel, OperationNameLabel}
+
+// Prometheus reports measurements of operations executed by graphql.Client as Prometheus metrics
+type Prometheus struct {
+	duration     *prometheus.HistogramVec
+	responses    *prometheus.CounterVec
+	errors       *prometheus.CounterVec
+	requestSize  *prometheus.HistogramVec
+	responseSize *prometheus.HistogramVec
+	retries      *prometheus.CounterVec
+}
+
+// NewPrometheus creates Prometheus metrics and registers them with the registerer
+func NewPrometheus(registerer prometheus.Registerer) (*Prometheus, error) {
+	p := &Prometheus{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of GraphQL operations, including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, operationLabels),
+		responses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "responses_total",
+			Help:      "Number of GraphQL operations by the status of the last HTTP response.",
+		}, append(operationLabels, StatusCodeLabel)),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Number of GraphQL errors by the code from extensions, empty if the error has no code.",
+		}, append(operationLabels, ErrorCodeLabel)),
+		requestSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_size_bytes",
+			Help:      "Size of request bodies sent for GraphQL operations.",
+			Buckets:   prometheus.ExponentialBuckets(64, 4, 8),
+		}, operationLabels),
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "response_size_bytes",
+			Help:      "Size of response bodies received for GraphQL operations.",
+			Buckets:   prometheus.ExponentialBuckets(64, 4, 8),
+		}, operationLabels),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Number of retries of GraphQL operations.",
+		}, operationLabels),
+	}
+
+	for _, collector := range []prometheus.Collector{p.duration, p.responses, p.errors, p.requestSize, p.responseSize, p.retries} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// ObserveOperation implements graphql.Metrics
+func (p *Prometheus) ObserveOperation(ctx context.Context, metrics graphql.OperationMetrics) {
+	operationType := string(metrics.OperationType)
+	operationName := metrics.OperationName
+
+	p.duration.WithLabelValues(operationType, operationName).Observe(metrics.Duration.Seconds())
+
+	if metrics.StatusCode != 0 {
+		p.responses.WithLabelValues(operationType, operationName, strconv.Itoa(metrics.StatusCode)).Inc()
+	}
+	for _, err := range metrics.Errors {
+		p.errors.WithLabelValues(operationType, operationName, err.Code()).Inc()
+	}
+
+	if metrics.RequestSize > 0 {
+		p.requestSize.WithLabelValues(operationType, operationName).Observe(float64(metrics.RequestSize))
+	}
+	if metrics.ResponseSize > 0 {
+		p.responseSize.WithLabelValues(operationType, operationName).Observe(float64(metrics.ResponseSize))
+	}
+
+	if metrics.Retries > 0 {
+		p.retries.WithLabelValues(operationType, operationName).Add(float64(metrics.Retries))
+	}
+}
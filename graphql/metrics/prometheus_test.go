@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func TestPrometheus(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := NewPrometheus(registry)
+	require.NoError(t, err)
+
+	metrics.ObserveOperation(context.Background(), graphql.OperationMetrics{
+		OperationType: graphql.Query,
+		OperationName: "Dogs",
+		Duration:      100 * time.Millisecond,
+		StatusCode:    http.StatusOK,
+		Errors: graphql.GraphQLErrors{
+			{Message: "forbidden", Extensions: map[string]interface{}{"code": "FORBIDDEN"}},
+			{Message: "forbidden", Extensions: map[string]interface{}{"code": "FORBIDDEN"}},
+			{Message: "failed"},
+		},
+		Retries:      2,
+		RequestSize:  100,
+		ResponseSize: 1000,
+	})
+	metrics.ObserveOperation(context.Background(), graphql.OperationMetrics{
+		OperationType: graphql.Query,
+		OperationName: "Dogs",
+		Duration:      time.Second,
+	})
+
+	err = testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP graphql_client_errors_total Number of GraphQL errors by the code from extensions, empty if the error has no code.
+# TYPE graphql_client_errors_total counter
+graphql_client_errors_total{code="",operation_name="Dogs",operation_type="query"} 1
+graphql_client_errors_total{code="FORBIDDEN",operation_name="Dogs",operation_type="query"} 2
+# HELP graphql_client_responses_total Number of GraphQL operations by the status of the last HTTP response.
+# TYPE graphql_client_responses_total counter
+graphql_client_responses_total{operation_name="Dogs",operation_type="query",status_code="200"} 1
+# HELP graphql_client_retries_total Number of retries of GraphQL operations.
+# TYPE graphql_client_retries_total counter
+graphql_client_retries_total{operation_name="Dogs",operation_type="query"} 2
+`), "graphql_client_errors_total", "graphql_client_responses_total", "graphql_client_retries_total")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.duration))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.requestSize))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.responseSize))
+
+	_, err = NewPrometheus(registry)
+	assert.Error(t, err, "should fail to register metrics twice")
+}
@@ -0,0 +1,42 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Response holds details of the response received for the GraphQL request.
+// Data of the response is decoded to responseOut passed to the Handler.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	// MediaType is the media type of the response without parameters.
+	// It is MediaTypeGraphQLResponse if the server follows GraphQL over HTTP specification
+	// and MediaTypeJSON for legacy servers.
+	MediaType string
+	// Errors are GraphQL errors from the body of the response
+	Errors GraphQLErrors
+	// Extensions are top-level extensions of the GraphQL response, such as query cost or tracing information
+	Extensions map[string]interface{}
+	// Duration is the time of the last HTTP request, from sending it to decoding the response.
+	// It does not include previous attempts, such as retries, or the time spent waiting between them.
+	Duration time.Duration
+}
+
+// Handler executes GraphQL request decoding data of the response to responseOut.
+// Response is nil if the request failed before the response was received.
+type Handler func(ctx context.Context, request Request, responseOut interface{}) (*Response, error)
+
+// Middleware wraps the Handler to add behavior before or after the request is executed,
+// for example to modify the Request, inspect decoded response or returned error.
+type Middleware func(next Handler) Handler
+
+// chainMiddlewares wraps the handler with middlewares so that the first middleware is the outermost one
+func chainMiddlewares(handler Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 )
 
@@ -10,24 +11,44 @@ type Operation struct {
 	Name      string
 	Requested interface{}
 	Input     OperationInput
+
+	// Idempotent marks mutation as safe to be retried
+	Idempotent bool
 }
 
 func (o Operation) ToQueryString(options ...ParserOptions) (string, error) {
-	parsedInput, err := ParseToGQLInput(o.Input, options...)
+	var opts = ParserOptions{}
+
+	if len(options) != 0 {
+		opts = options[0]
+	}
+
+	parsedInput, err := opts.parseToGQLInput(o.Input, 1)
 	if err != nil {
 		return "", fmt.Errorf("failed to create query string, %w", err)
 	}
 
-	return fmt.Sprintf(`%s {
-	result: %s(%s) %s
-}`, o.Type, o.Name, parsedInput, ParseToGQLQuery(o.Requested)), nil
+	field := o.Name
+	if parsedInput != "" {
+		field = fmt.Sprintf("%s(%s)", o.Name, parsedInput)
+	}
+
+	selectionSet, err := opts.parseToGQLQuery(reflect.ValueOf(o.Requested), 1)
+	if err != nil {
+		return "", fmt.Errorf("failed to create query string, failed to parse requested type, %w", err)
+	}
+
+	result := opts.printer().field(opts.printer().keyValue("result", field), selectionSet)
+
+	return opts.printer().operation(o.Type, []string{result}), nil
 }
 
 type OperationType string
 
 const (
-	Query    OperationType = "query"
-	Mutation OperationType = "mutation"
+	Query        OperationType = "query"
+	Mutation     OperationType = "mutation"
+	Subscription OperationType = "subscription"
 )
 
 type OperationInput map[string]interface{}
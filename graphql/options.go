@@ -1,15 +1,51 @@
 package graphql
 
-import "net/http"
-
-type logger func(string)
+import (
+	"context"
+	"net/http"
+	"time"
+)
 
 type options struct {
 	parserOptions ParserOptions
 	httpClient    *http.Client
-	logger        logger
+	logger        Logger
+	redaction     RedactionPolicy
+	metrics       Metrics
+	middlewares   []Middleware
+	retryPolicy   *RetryPolicy
+
+	circuitBreakerPolicy *CircuitBreakerPolicy
+
+	headers         http.Header
+	headerProviders []HeaderProvider
+
+	tokenSource *cachingTokenSource
+
+	getQueries   bool
+	maxURLLength int
+
+	persistedQueries *persistedQueries
+
+	batching *batchingOptions
+
+	requestCompression *requestCompression
+	responseCodecs     []Codec
+
+	limits *limiter
+
+	endpoints      []string
+	endpointPolicy EndpointPolicy
 }
 
+// HeaderProvider returns headers that should be added to the request.
+// It is called before execution of every request, which allows to provide values such as refreshed tokens
+// or request IDs taken from the context.
+type HeaderProvider func(ctx context.Context) (http.Header, error)
+
+// DefaultMaxURLLength is the maximum length of the URL of GET requests, which is safe for most servers and proxies
+const DefaultMaxURLLength = 2048
+
 // Option overrides behavior of GraphQLClient.
 type Option interface {
 	apply(*options)
@@ -27,14 +63,182 @@ func WithHTTPClient(client *http.Client) Option {
 	})
 }
 
-func WithLogger(logger func(string)) Option {
+// WithLogger sets the logger used to log executed requests and their results
+func WithLogger(logger Logger) Option {
 	return optionFunc(func(o *options) {
 		o.logger = logger
 	})
 }
 
+// WithRedaction sets the policy used to redact sensitive data before it is logged.
+// DefaultRedactionPolicy is used if not set.
+func WithRedaction(policy RedactionPolicy) Option {
+	return optionFunc(func(o *options) {
+		o.redaction = policy
+	})
+}
+
+// WithMetrics sets the receiver of measurements of executed operations, such as their duration, response status,
+// GraphQL errors, sizes of bodies and the number of retries
+func WithMetrics(metrics Metrics) Option {
+	return optionFunc(func(o *options) {
+		o.metrics = metrics
+	})
+}
+
 func WithParserOptions(parserOpts ParserOptions) Option {
 	return optionFunc(func(o *options) {
 		o.parserOptions = parserOpts
 	})
 }
+
+// WithMiddleware adds middlewares wrapping execution of every request.
+// Middlewares are called in the order in which they were provided.
+func WithMiddleware(middleware ...Middleware) Option {
+	return optionFunc(func(o *options) {
+		o.middlewares = append(o.middlewares, middleware...)
+	})
+}
+
+// WithRetry enables retries of failed requests according to the policy.
+// Mutations are retried only if marked as idempotent, see Operation.Idempotent and Request.Idempotent.
+func WithRetry(policy RetryPolicy) Option {
+	return optionFunc(func(o *options) {
+		o.retryPolicy = &policy
+	})
+}
+
+// WithCircuitBreaker enables the circuit breaker, which rejects requests with ErrCircuitOpen without executing them
+// after consecutive failures, until the server recovers. Failed retries are counted as a single failure.
+func WithCircuitBreaker(policy CircuitBreakerPolicy) Option {
+	return optionFunc(func(o *options) {
+		o.circuitBreakerPolicy = &policy
+	})
+}
+
+// WithEndpoints adds endpoints to which requests are sent, besides the one passed to NewClient, which is the first one.
+// Requests are distributed between endpoints according to the policy and endpoints failing with network errors
+// or 5xx statuses are skipped. Queries and idempotent mutations are sent to the next endpoint if the one they were sent to is unavailable.
+func WithEndpoints(policy EndpointPolicy, endpoints ...string) Option {
+	return optionFunc(func(o *options) {
+		o.endpoints = append(o.endpoints, endpoints...)
+		o.endpointPolicy = policy
+	})
+}
+
+// WithHeaders adds headers to every request executed by the client.
+//
+// If the same header is specified on multiple levels the values are not merged, the header is overridden instead.
+// Headers passed with the request take precedence over the ones from providers,
+// which take precedence over the headers specified with WithHeaders.
+func WithHeaders(header http.Header) Option {
+	return optionFunc(func(o *options) {
+		o.headers = overrideHeaders(o.headers, header)
+	})
+}
+
+// WithHeaderProvider adds the provider of headers that are added to every request executed by the client.
+// Providers are called in the order in which they were added, headers from the later providers take precedence.
+// See WithHeaders for the details of the precedence rules.
+func WithHeaderProvider(provider HeaderProvider) Option {
+	return optionFunc(func(o *options) {
+		o.headerProviders = append(o.headerProviders, provider)
+	})
+}
+
+// WithTokenSource enables authentication of requests with tokens from the token source.
+// Tokens are cached until they expire. If the server responds with 401 status or with UNAUTHENTICATED error code,
+// the token is refreshed and the request is retried once.
+func WithTokenSource(tokenSource TokenSource) Option {
+	return optionFunc(func(o *options) {
+		o.tokenSource = newCachingTokenSource(tokenSource)
+	})
+}
+
+// WithGETForQueries enables sending queries with GET requests, which allows responses to be cached by CDNs and proxies.
+// Mutations, and queries for which the URL would exceed maxURLLength, are still sent with POST.
+// If maxURLLength is not positive, DefaultMaxURLLength is used.
+func WithGETForQueries(maxURLLength int) Option {
+	return optionFunc(func(o *options) {
+		if maxURLLength <= 0 {
+			maxURLLength = DefaultMaxURLLength
+		}
+		o.getQueries = true
+		o.maxURLLength = maxURLLength
+	})
+}
+
+// WithAutomaticPersistedQueries enables Automatic Persisted Queries.
+// Requests are sent with the SHA-256 hash of the query instead of the full query,
+// which is sent only if the server does not know the hash yet.
+// Combined with WithGETForQueries it allows for caching of responses for large queries.
+func WithAutomaticPersistedQueries() Option {
+	return optionFunc(func(o *options) {
+		o.persistedQueries = newPersistedQueries()
+	})
+}
+
+// WithBatching enables batching of operations executed concurrently.
+// Operations are collected for the duration of the window, or until maxSize operations are collected,
+// and sent as a single POST request with JSON array of operations. Non-positive maxSize does not limit the batch size.
+// Only operations with the same headers are batched together.
+func WithBatching(window time.Duration, maxSize int) Option {
+	return optionFunc(func(o *options) {
+		o.batching = &batchingOptions{
+			window:  window,
+			maxSize: maxSize,
+		}
+	})
+}
+
+// WithRequestCompression compresses bodies of requests with the codec if they are larger than the threshold in bytes.
+// The server needs to support the Content-Encoding of requests.
+func WithRequestCompression(codec Codec, threshold int) Option {
+	return optionFunc(func(o *options) {
+		o.requestCompression = &requestCompression{
+			codec:     codec,
+			threshold: threshold,
+		}
+	})
+}
+
+// WithResponseCompression requests responses compressed with one of the codecs, listed in the order of preference,
+// and decompresses them. If no codecs are provided gzip and deflate are used.
+func WithResponseCompression(codecs ...Codec) Option {
+	return optionFunc(func(o *options) {
+		if len(codecs) == 0 {
+			codecs = defaultCodecs
+		}
+		o.responseCodecs = codecs
+	})
+}
+
+// WithRateLimit limits the rate of HTTP requests sent by the client to requestsPerSecond, allowing bursts of up to burst requests.
+// Requests wait until they are allowed or their context is done. Each retry, persisted query registration
+// or request sent to the next endpoint is counted separately, while the batch is counted as a single request.
+// The option is ignored if requestsPerSecond is not positive.
+//
+// When limits are enabled, with WithRateLimit or WithMaxConcurrency, and the server reports exceeded rate limit
+// with 429 or 503 status, or with RATE_LIMITED or THROTTLED error code, requests are paused for the time specified
+// by the Retry-After header or the retryAfter extension of the error.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return optionFunc(func(o *options) {
+		if !(requestsPerSecond > 0) {
+			return
+		}
+		o.initLimits().bucket = newTokenBucket(requestsPerSecond, burst)
+	})
+}
+
+// WithMaxConcurrency limits the number of HTTP requests executed concurrently by the client.
+// Requests wait until other requests finish, and their response body is read, or their context is done.
+// The option is ignored if maxConcurrency is less than 1.
+// See WithRateLimit for the details of handling rate limits reported by the server.
+func WithMaxConcurrency(maxConcurrency int) Option {
+	return optionFunc(func(o *options) {
+		if maxConcurrency < 1 {
+			return
+		}
+		o.initLimits().slots = make(chan struct{}, maxConcurrency)
+	})
+}
@@ -0,0 +1,252 @@
+package graphql
+
+import (
+	"strings"
+)
+
+// printer controls the layout of generated GraphQL documents.
+// It is shared by the query and input parsers so that both produce consistent output.
+type printer struct {
+	// compact determines if the document should be printed in a single line without redundant whitespaces
+	compact bool
+}
+
+// block wraps elements with open and close delimiters.
+// In the default mode each element is placed in a separate line indented one level deeper than the delimiters,
+// in the compact mode elements are joined with the separator or with a single space if the separator is empty.
+// Empty block is printed in a single line.
+func (p printer) block(open, close string, elements []string, separator string, indent int) string {
+	if len(elements) == 0 {
+		return open + close
+	}
+	if p.compact {
+		if separator == "" {
+			separator = " "
+		}
+		return open + strings.Join(elements, separator) + close
+	}
+
+	block := open
+	for i, elem := range elements {
+		block += "\n" + tabsIndent(indent+1) + elem
+		if i < len(elements)-1 {
+			block += separator
+		}
+	}
+
+	return block + "\n" + tabsIndent(indent) + close
+}
+
+// keyValue formats an argument or an input object field
+func (p printer) keyValue(key, value string) string {
+	if p.compact {
+		return key + ":" + value
+	}
+
+	return key + ": " + value
+}
+
+// field formats a selection field together with its selection set if it is not empty
+func (p printer) field(name, selectionSet string) string {
+	if selectionSet == "" {
+		return name
+	}
+	if p.compact {
+		return name + selectionSet
+	}
+
+	return name + " " + selectionSet
+}
+
+// arguments formats a list of arguments, without the surrounding parentheses
+func (p printer) arguments(args []string) string {
+	if p.compact {
+		return strings.Join(args, ",")
+	}
+
+	return strings.Join(args, ", ")
+}
+
+// operation formats the whole operation document
+func (p printer) operation(operationType OperationType, selectionSet []string) string {
+	if p.compact {
+		return string(operationType) + p.block("{", "}", selectionSet, "", 0)
+	}
+
+	return string(operationType) + " " + p.block("{", "}", selectionSet, "", 0)
+}
+
+func tabsIndent(tabsCount int) string {
+	return strings.Repeat("\t", tabsCount)
+}
+
+// PrettyPrint formats GraphQL document to the human readable form, with each field placed in a separate line.
+// It is meant to be used for logging and debugging of documents produced with the compact ParserOptions,
+// the document is not validated, so the output of the invalid document is not specified.
+func PrettyPrint(document string) string {
+	var (
+		builder        strings.Builder
+		scopes         []byte
+		indent         int
+		prevToken      string
+		inlineFragment bool
+		// variableType is set while printing the type of the variable definition, in which lists are printed inline
+		variableType bool
+	)
+
+	tokens := tokenize(document)
+
+	currentScope := func() byte {
+		if len(scopes) == 0 {
+			return 0
+		}
+		return scopes[len(scopes)-1]
+	}
+
+	newLine := func() {
+		builder.WriteString("\n" + tabsIndent(indent))
+	}
+
+	separate := func() {
+		switch currentScope() {
+		case '{':
+			newLine()
+		case '[':
+			builder.WriteString(",")
+			newLine()
+		case '(':
+			builder.WriteString(", ")
+		default:
+			builder.WriteString(" ")
+		}
+	}
+
+	for i, token := range tokens {
+		if currentScope() == '(' && token == ":" && i >= 2 && tokens[i-2] == "$" {
+			variableType = true
+		} else if token == "=" || token == "$" || token == "@" || token == ")" {
+			variableType = false
+		}
+
+		switch {
+		case len(scopes) == 0 && prevToken == "}":
+			// Each top-level definition starts in a new line
+			newLine()
+			prevToken = ""
+		case variableType && (token == "[" || token == "]"):
+			builder.WriteString(token)
+			prevToken = token
+			continue
+		}
+
+		switch token {
+		case "{", "[":
+			if token == "{" && (isNameToken(prevToken) || prevToken == ")") {
+				builder.WriteString(" ")
+			} else if endsItem(prevToken) {
+				separate()
+			}
+			builder.WriteString(token)
+			scopes = append(scopes, token[0])
+			indent++
+			newLine()
+		case "}", "]":
+			if len(scopes) > 0 {
+				scopes = scopes[:len(scopes)-1]
+				indent--
+			}
+			newLine()
+			builder.WriteString(token)
+		case "(":
+			builder.WriteString(token)
+			scopes = append(scopes, token[0])
+		case ")":
+			if len(scopes) > 0 {
+				scopes = scopes[:len(scopes)-1]
+			}
+			builder.WriteString(token)
+		case ":":
+			builder.WriteString(": ")
+		case "=":
+			builder.WriteString(" = ")
+		case "!":
+			builder.WriteString(token)
+		default:
+			if token == "@" || inlineFragment || prevToken == "..." {
+				builder.WriteString(" ")
+			} else if endsItem(prevToken) {
+				separate()
+			}
+			builder.WriteString(token)
+		}
+
+		inlineFragment = prevToken == "..." && token == "on"
+		prevToken = token
+	}
+
+	return builder.String()
+}
+
+// tokenize splits GraphQL document to tokens skipping whitespaces, commas and comments
+func tokenize(document string) []string {
+	var tokens []string
+
+	for i := 0; i < len(document); {
+		char := document[i]
+
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r' || char == ',':
+			i++
+		case char == '#':
+			for i < len(document) && document[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(document[i:], `"""`):
+			end := strings.Index(document[i+3:], `"""`)
+			if end == -1 {
+				tokens = append(tokens, document[i:])
+				return tokens
+			}
+			tokens = append(tokens, document[i:i+3+end+3])
+			i += 3 + end + 3
+		case char == '"':
+			end := i + 1
+			for end < len(document) && document[end] != '"' {
+				if document[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(document) {
+				tokens = append(tokens, document[i:])
+				return tokens
+			}
+			tokens = append(tokens, document[i:end+1])
+			i = end + 1
+		case strings.HasPrefix(document[i:], "..."):
+			tokens = append(tokens, "...")
+			i += 3
+		case strings.IndexByte("{}[]():=!$@|&", char) != -1:
+			tokens = append(tokens, string(char))
+			i++
+		default:
+			end := i
+			for end < len(document) && strings.IndexByte(" \t\n\r,#\"{}[]():=!$@|&", document[end]) == -1 {
+				end++
+			}
+			tokens = append(tokens, document[i:end])
+			i = end
+		}
+	}
+
+	return tokens
+}
+
+func isNameToken(token string) bool {
+	return token != "" && strings.IndexByte("{}[]():=!$@|&\".", token[0]) == -1
+}
+
+// endsItem determines if the token may be the last token of a field, an argument or a value
+func endsItem(token string) bool {
+	return token != "" && strings.IndexByte("{[(:=$@|&.", token[0]) == -1
+}
@@ -0,0 +1,131 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ToQueryString(t *testing.T) {
+	operation := Operation{
+		Type:      Mutation,
+		Name:      "create",
+		Requested: jsonTaggedStruct{},
+		Input: OperationInput{
+			"id": "abcd",
+			"in": jsonTaggedStruct{StringField: "test", SliceField: []string{"a", "b"}},
+		},
+	}
+
+	t.Run("should print operation in default mode", func(t *testing.T) {
+		query, err := operation.ToQueryString()
+		require.NoError(t, err)
+
+		assert.Equal(t, `mutation {
+	result: create(id: "abcd", in: {
+		stringField: "test"
+		intField: 0
+		sliceField: [
+			"a",
+			"b"
+		]
+	}) {
+		stringField
+		intField
+		sliceField
+	}
+}`, query)
+	})
+
+	t.Run("should print operation in compact mode", func(t *testing.T) {
+		query, err := operation.ToQueryString(ParserOptions{Compact: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, `mutation{result:create(id:"abcd",in:{stringField:"test" intField:0 sliceField:["a","b"]}){stringField intField sliceField}}`, query)
+	})
+
+	t.Run("should skip parentheses if there is no input", func(t *testing.T) {
+		query, err := Operation{Type: Query, Name: "names", Requested: []string{}}.ToQueryString(ParserOptions{Compact: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, `query{result:names}`, query)
+	})
+}
+
+func Test_PrettyPrint(t *testing.T) {
+	t.Run("should format compact operation the same as default mode", func(t *testing.T) {
+		operation := Operation{
+			Type:      Query,
+			Name:      "complex",
+			Requested: []complexStruct{},
+			Input: OperationInput{
+				"in": slicesOfPointersStruct{
+					Name:             "test",
+					SimpleStructPtrs: []*simpleStruct{{StringField: "first"}, {IntField: 2}},
+				},
+				"limit": 10,
+			},
+		}
+
+		compact, err := operation.ToQueryString(ParserOptions{Compact: true})
+		require.NoError(t, err)
+		expected, err := operation.ToQueryString()
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, PrettyPrint(compact))
+		assert.Equal(t, expected, PrettyPrint(expected))
+	})
+
+	t.Run("should format hand written document", func(t *testing.T) {
+		document := `query Dog($id: ID!, $withName: Boolean = true) { dog(id: $id) { id ... on Dog { name @include(if: $withName) } ...Owner } } # comment`
+
+		assert.Equal(t, `query Dog($id: ID!, $withName: Boolean = true) {
+	dog(id: $id) {
+		id
+		... on Dog {
+			name @include(if: $withName)
+		}
+		... Owner
+	}
+}`, PrettyPrint(document))
+	})
+
+	t.Run("should print list types of variables inline", func(t *testing.T) {
+		document := `query Dogs($ids: [ID!]!, $tags: [[String]] = [["a"]]) { dogs(ids: $ids, tags: $tags) { id } }`
+
+		assert.Equal(t, `query Dogs($ids: [ID!]!, $tags: [[String]] = [
+	[
+		"a"
+	]
+]) {
+	dogs(ids: $ids, tags: $tags) {
+		id
+	}
+}`, PrettyPrint(document))
+	})
+
+	t.Run("should place top-level definitions in separate lines", func(t *testing.T) {
+		document := `query Dog { dog { ...Fields } } fragment Fields on Dog { id name }`
+
+		assert.Equal(t, `query Dog {
+	dog {
+		... Fields
+	}
+}
+fragment Fields on Dog {
+	id
+	name
+}`, PrettyPrint(document))
+	})
+
+	t.Run("should not split strings", func(t *testing.T) {
+		document := `{result:search(phrase:"{ a, b } # \" c"){name}}`
+
+		assert.Equal(t, `{
+	result: search(phrase: "{ a, b } # \" c") {
+		name
+	}
+}`, PrettyPrint(document))
+	})
+}
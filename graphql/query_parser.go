@@ -9,43 +9,85 @@ import (
 // TODO: support some tags to allow user to skip some fields
 
 const (
-	jsonTagKey = "json"
+	jsonTagKey    = "json"
+	typeNameField = "__typename"
 )
 
-func ParseToGQLQuery(data interface{}) string {
-	return parseToGQLQuery(data, 0)
+// TypeName can be embedded in the requested types to receive the name of the GraphQL object type.
+// Use it together with IncludeTypeName ParserOptions to have the __typename requested for every object.
+type TypeName struct {
+	Typename string `json:"__typename"`
 }
 
-func parseToGQLQuery(data interface{}, indent int) string {
-	reflectVal := reflect.ValueOf(data)
+// GetTypeName returns the name of the GraphQL object type received in the response
+func (t TypeName) GetTypeName() string {
+	return t.Typename
+}
+
+func ParseToGQLQuery(data interface{}, options ...ParserOptions) (string, error) {
+	var opts = ParserOptions{}
+
+	if len(options) != 0 {
+		opts = options[0]
+	}
+
+	query, err := opts.parseToGQLQuery(reflect.ValueOf(data), 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse to GQL query, %w", err)
+	}
 
+	return query, nil
+}
+
+func (o ParserOptions) parseToGQLQuery(reflectVal reflect.Value, indent int) (string, error) {
 	reflectVal = unwrapPointerOrInterface(reflectVal)
 
-	if reflectVal.Kind() == reflect.Struct {
-		fieldsString := "{"
+	switch reflectVal.Kind() {
+	case reflect.Struct:
+		if isMarshaledStruct(reflectVal.Type()) {
+			// Custom scalar does not have the selection set
+			return "", nil
+		}
+
+		typeFields := structFields(reflectVal.Type())
+		if len(typeFields) == 0 {
+			return "", fmt.Errorf("unsupported type %s, it has no exported fields", reflectVal.Type())
+		}
+		fields := make([]string, 0, len(typeFields)+1)
+		includesTypeName := false
 
-		for i := 0; i < reflectVal.NumField(); i++ {
-			queriedName := reflectVal.Type().Field(i).Tag.Get(jsonTagKey)
-			if queriedName == "" {
-				queriedName = reflectVal.Type().Field(i).Name
+		for _, typeField := range typeFields {
+			if typeField.name == typeNameField {
+				includesTypeName = true
 			}
 
-			// TODO: this space may be confusing, consider removing it
-			field := fmt.Sprintf("%s %s", queriedName, parseToGQLQuery(reflectVal.Field(i).Interface(), indent+1))
-			fieldsString = fmt.Sprintf("%s\n%s%s", fieldsString, tabsIndent(indent+1), field)
-		}
+			fieldVal, ok := fieldByIndex(reflectVal, typeField.index)
+			if !ok {
+				// Field promoted through nil embedded pointer, its type is still needed to build the selection
+				fieldVal = reflect.New(typeField.typ).Elem()
+			}
 
-		fieldsString += "\n" + tabsIndent(indent) + "}"
+			selectionSet, err := o.parseToGQLQuery(fieldVal, indent+1)
+			if err != nil {
+				return "", fmt.Errorf("failed to parse %s field: %w", typeField.name, err)
+			}
 
-		return fieldsString
-	}
+			fields = append(fields, o.printer().field(typeField.name, selectionSet))
+		}
+
+		if o.IncludeTypeName && !includesTypeName {
+			fields = append(fields, typeNameField)
+		}
 
-	if reflectVal.Kind() == reflect.Slice || reflectVal.Kind() == reflect.Array {
+		return o.printer().block("{", "}", fields, "", indent), nil
+	case reflect.Slice, reflect.Array:
 		sliceElemObj := reflectVal.Type().Elem()
-		return parseToGQLQuery(reflect.New(sliceElemObj).Interface(), indent)
+		return o.parseToGQLQuery(reflect.New(sliceElemObj), indent)
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return "", fmt.Errorf("unsupported type %s", reflectVal.Type())
 	}
 
-	return ""
+	return "", nil
 }
 
 func unwrapPointerOrInterface(reflectVal reflect.Value) reflect.Value {
@@ -54,7 +96,11 @@ func unwrapPointerOrInterface(reflectVal reflect.Value) reflect.Value {
 
 		// In case that the kind was Ptr and the value is nil then the Elem().Kind() is invalid
 		// to inspect the fields we need to instantiate new object
+		// nil interface does not carry any type information so there is nothing to inspect
 		if reflectValElem.Kind() == reflect.Invalid {
+			if reflectVal.Kind() == reflect.Interface {
+				return reflectValElem
+			}
 			reflectVal = reflect.New(reflectVal.Type().Elem())
 		} else {
 			reflectVal = reflectValElem
@@ -63,11 +109,3 @@ func unwrapPointerOrInterface(reflectVal reflect.Value) reflect.Value {
 
 	return reflectVal
 }
-
-func tabsIndent(tabsCount int) (tabs string) {
-	for i := 0; i < tabsCount; i++ {
-		tabs += "\t"
-	}
-
-	return tabs
-}
@@ -1,9 +1,12 @@
 package graphql
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type simpleStruct struct {
@@ -72,6 +75,84 @@ type mapsStruct struct {
 	NilMapPlaceholder map[string]string
 }
 
+type namedStruct struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+type describedStruct struct {
+	Description string `json:"description"`
+	Kind        string `json:"kind"`
+}
+
+type embeddingStruct struct {
+	namedStruct
+	*describedStruct
+	ID string `json:"id"`
+}
+
+type overridingStruct struct {
+	embeddingStruct
+	Kind  string           `json:"kind,omitempty"`
+	Extra jsonTaggedStruct `json:"extra"`
+	Skip  string           `json:"-"`
+}
+
+type untaggedTitleStruct struct {
+	Title string
+}
+
+type taggedTitleStruct struct {
+	Heading string `json:"Title"`
+}
+
+type Named struct {
+	Name string `json:"name"`
+}
+
+type taggedEmbeddingStruct struct {
+	untaggedTitleStruct
+	taggedTitleStruct
+	Named `json:"named"`
+}
+
+type unexportedFieldsStruct struct {
+	Name        string `json:"name"`
+	internal    string
+	namedStruct `json:"named"`
+	*describedStruct
+}
+
+type event struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	Amount    money     `json:"amount"`
+}
+
+// money is marshaled to JSON with pointer receiver
+type money struct {
+	cents int
+}
+
+func (m *money) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%02d", m.cents/100, m.cents%100)), nil
+}
+
+type noExportedFieldsStruct struct {
+	internal string
+}
+
+type unsupportedTypesStruct struct {
+	Name     string
+	Callback func()
+}
+
+type typeNamedStruct struct {
+	TypeName
+	Name  string           `json:"name"`
+	Inner jsonTaggedStruct `json:"inner"`
+}
+
 var nilSliceOfSimpleStructs []*simpleStruct
 
 func Test_ParseToGQLQuery(t *testing.T) {
@@ -91,11 +172,11 @@ func Test_ParseToGQLQuery(t *testing.T) {
 				InterfaceField: "test",
 			},
 			expectedQuery: `{
-	StringField 
-	IntField 
-	StringPtrField 
-	BoolField 
-	InterfaceField 
+	StringField
+	IntField
+	StringPtrField
+	BoolField
+	InterfaceField
 }`,
 		},
 		{
@@ -108,11 +189,11 @@ func Test_ParseToGQLQuery(t *testing.T) {
 				InterfaceField: "test",
 			},
 			expectedQuery: `{
-	StringField 
-	IntField 
-	StringPtrField 
-	BoolField 
-	InterfaceField 
+	StringField
+	IntField
+	StringPtrField
+	BoolField
+	InterfaceField
 }`,
 		},
 		{
@@ -125,16 +206,16 @@ func Test_ParseToGQLQuery(t *testing.T) {
 				InterfaceField: simpleStruct{},
 			},
 			expectedQuery: `{
-	StringField 
-	IntField 
-	StringPtrField 
-	BoolField 
+	StringField
+	IntField
+	StringPtrField
+	BoolField
 	InterfaceField {
-		StringField 
-		IntField 
-		StringPtrField 
-		BoolField 
-		InterfaceField 
+		StringField
+		IntField
+		StringPtrField
+		BoolField
+		InterfaceField
 	}
 }`,
 		},
@@ -148,16 +229,16 @@ func Test_ParseToGQLQuery(t *testing.T) {
 				InterfaceField: []simpleStruct{},
 			},
 			expectedQuery: `{
-	StringField 
-	IntField 
-	StringPtrField 
-	BoolField 
+	StringField
+	IntField
+	StringPtrField
+	BoolField
 	InterfaceField {
-		StringField 
-		IntField 
-		StringPtrField 
-		BoolField 
-		InterfaceField 
+		StringField
+		IntField
+		StringPtrField
+		BoolField
+		InterfaceField
 	}
 }`,
 		},
@@ -170,10 +251,10 @@ func Test_ParseToGQLQuery(t *testing.T) {
 				BoolsSlice:      []bool{true, false},
 			},
 			expectedQuery: `{
-	StringsSlice 
-	IntsSlice 
-	StringPtrsSlice 
-	BoolsSlice 
+	StringsSlice
+	IntsSlice
+	StringPtrsSlice
+	BoolsSlice
 }`,
 		},
 		{
@@ -185,10 +266,10 @@ func Test_ParseToGQLQuery(t *testing.T) {
 				BoolsSlice:      nil,
 			},
 			expectedQuery: `{
-	StringsSlice 
-	IntsSlice 
-	StringPtrsSlice 
-	BoolsSlice 
+	StringsSlice
+	IntsSlice
+	StringPtrsSlice
+	BoolsSlice
 }`,
 		},
 		{
@@ -200,20 +281,20 @@ func Test_ParseToGQLQuery(t *testing.T) {
 				SliceStructField:  sliceStruct{},
 			},
 			expectedQuery: `{
-	StringField 
-	IntField 
+	StringField
+	IntField
 	SimpleStructField {
-		StringField 
-		IntField 
-		StringPtrField 
-		BoolField 
-		InterfaceField 
+		StringField
+		IntField
+		StringPtrField
+		BoolField
+		InterfaceField
 	}
 	SliceStructField {
-		StringsSlice 
-		IntsSlice 
-		StringPtrsSlice 
-		BoolsSlice 
+		StringsSlice
+		IntsSlice
+		StringPtrsSlice
+		BoolsSlice
 	}
 }`,
 		},
@@ -228,26 +309,26 @@ func Test_ParseToGQLQuery(t *testing.T) {
 				SliceStructField: sliceStruct{},
 			},
 			expectedQuery: `{
-	StringField 
-	IntField 
+	StringField
+	IntField
 	SimpleStructField {
-		StringField 
-		IntField 
-		StringPtrField 
-		BoolField 
+		StringField
+		IntField
+		StringPtrField
+		BoolField
 		InterfaceField {
-			StringField 
-			IntField 
-			StringPtrField 
-			BoolField 
-			InterfaceField 
+			StringField
+			IntField
+			StringPtrField
+			BoolField
+			InterfaceField
 		}
 	}
 	SliceStructField {
-		StringsSlice 
-		IntsSlice 
-		StringPtrsSlice 
-		BoolsSlice 
+		StringsSlice
+		IntsSlice
+		StringPtrsSlice
+		BoolsSlice
 	}
 }`,
 		},
@@ -260,46 +341,46 @@ func Test_ParseToGQLQuery(t *testing.T) {
 				SliceStructsField:    []sliceStruct{},
 			},
 			expectedQuery: `{
-	StringField 
+	StringField
 	EmbeddedStructField {
-		StringField 
-		IntField 
+		StringField
+		IntField
 		SimpleStructField {
-			StringField 
-			IntField 
-			StringPtrField 
-			BoolField 
-			InterfaceField 
+			StringField
+			IntField
+			StringPtrField
+			BoolField
+			InterfaceField
 		}
 		SliceStructField {
-			StringsSlice 
-			IntsSlice 
-			StringPtrsSlice 
-			BoolsSlice 
+			StringsSlice
+			IntsSlice
+			StringPtrsSlice
+			BoolsSlice
 		}
 	}
 	EmbeddedStructsField {
-		StringField 
-		IntField 
+		StringField
+		IntField
 		SimpleStructField {
-			StringField 
-			IntField 
-			StringPtrField 
-			BoolField 
-			InterfaceField 
+			StringField
+			IntField
+			StringPtrField
+			BoolField
+			InterfaceField
 		}
 		SliceStructField {
-			StringsSlice 
-			IntsSlice 
-			StringPtrsSlice 
-			BoolsSlice 
+			StringsSlice
+			IntsSlice
+			StringPtrsSlice
+			BoolsSlice
 		}
 	}
 	SliceStructsField {
-		StringsSlice 
-		IntsSlice 
-		StringPtrsSlice 
-		BoolsSlice 
+		StringsSlice
+		IntsSlice
+		StringPtrsSlice
+		BoolsSlice
 	}
 }`,
 		},
@@ -311,9 +392,9 @@ func Test_ParseToGQLQuery(t *testing.T) {
 				SliceField:  []string{},
 			},
 			expectedQuery: `{
-	stringField 
-	intField 
-	sliceField 
+	stringField
+	intField
+	sliceField
 }`,
 		},
 		{
@@ -325,17 +406,17 @@ func Test_ParseToGQLQuery(t *testing.T) {
 				JsonTaggedStructs: nil,
 			},
 			expectedQuery: `{
-	stringField 
-	intField 
+	stringField
+	intField
 	jsonTaggedStruct {
-		stringField 
-		intField 
-		sliceField 
+		stringField
+		intField
+		sliceField
 	}
 	jsonTaggedStructs {
-		stringField 
-		intField 
-		sliceField 
+		stringField
+		intField
+		sliceField
 	}
 }`,
 		},
@@ -348,62 +429,62 @@ func Test_ParseToGQLQuery(t *testing.T) {
 			name: "slice of pointers",
 			data: []*simpleStruct{},
 			expectedQuery: `{
-	StringField 
-	IntField 
-	StringPtrField 
-	BoolField 
-	InterfaceField 
+	StringField
+	IntField
+	StringPtrField
+	BoolField
+	InterfaceField
 }`,
 		},
 		{
 			name: "nil slice of pointers",
 			data: nilSliceOfSimpleStructs,
 			expectedQuery: `{
-	StringField 
-	IntField 
-	StringPtrField 
-	BoolField 
-	InterfaceField 
+	StringField
+	IntField
+	StringPtrField
+	BoolField
+	InterfaceField
 }`,
 		},
 		{
 			name: "slice of structs",
 			data: []simpleStruct{},
 			expectedQuery: `{
-	StringField 
-	IntField 
-	StringPtrField 
-	BoolField 
-	InterfaceField 
+	StringField
+	IntField
+	StringPtrField
+	BoolField
+	InterfaceField
 }`,
 		},
 		{
 			name: "included pointers struct with nil pointers",
 			data: includedPointersStruct{},
 			expectedQuery: `{
-	name 
+	name
 	simpleStructPtr {
-		StringField 
-		IntField 
-		StringPtrField 
-		BoolField 
-		InterfaceField 
+		StringField
+		IntField
+		StringPtrField
+		BoolField
+		InterfaceField
 	}
 	embeddedStructPtr {
-		StringField 
-		IntField 
+		StringField
+		IntField
 		SimpleStructField {
-			StringField 
-			IntField 
-			StringPtrField 
-			BoolField 
-			InterfaceField 
+			StringField
+			IntField
+			StringPtrField
+			BoolField
+			InterfaceField
 		}
 		SliceStructField {
-			StringsSlice 
-			IntsSlice 
-			StringPtrsSlice 
-			BoolsSlice 
+			StringsSlice
+			IntsSlice
+			StringPtrsSlice
+			BoolsSlice
 		}
 	}
 }`,
@@ -412,29 +493,29 @@ func Test_ParseToGQLQuery(t *testing.T) {
 			name: "included pointers struct with non nil pointers",
 			data: includedPointersStruct{SimpleStructPtr: &simpleStruct{}, EmbeddedStructPtr: &embeddedStruct{}},
 			expectedQuery: `{
-	name 
+	name
 	simpleStructPtr {
-		StringField 
-		IntField 
-		StringPtrField 
-		BoolField 
-		InterfaceField 
+		StringField
+		IntField
+		StringPtrField
+		BoolField
+		InterfaceField
 	}
 	embeddedStructPtr {
-		StringField 
-		IntField 
+		StringField
+		IntField
 		SimpleStructField {
-			StringField 
-			IntField 
-			StringPtrField 
-			BoolField 
-			InterfaceField 
+			StringField
+			IntField
+			StringPtrField
+			BoolField
+			InterfaceField
 		}
 		SliceStructField {
-			StringsSlice 
-			IntsSlice 
-			StringPtrsSlice 
-			BoolsSlice 
+			StringsSlice
+			IntsSlice
+			StringPtrsSlice
+			BoolsSlice
 		}
 	}
 }`,
@@ -443,29 +524,29 @@ func Test_ParseToGQLQuery(t *testing.T) {
 			name: "slices of pointers struct with non nil slices",
 			data: slicesOfPointersStruct{SimpleStructPtrs: []*simpleStruct{}, EmbeddedStructPtrs: []*embeddedStruct{}},
 			expectedQuery: `{
-	name 
+	name
 	simpleStructPtrs {
-		StringField 
-		IntField 
-		StringPtrField 
-		BoolField 
-		InterfaceField 
+		StringField
+		IntField
+		StringPtrField
+		BoolField
+		InterfaceField
 	}
 	embeddedStructPtrs {
-		StringField 
-		IntField 
+		StringField
+		IntField
 		SimpleStructField {
-			StringField 
-			IntField 
-			StringPtrField 
-			BoolField 
-			InterfaceField 
+			StringField
+			IntField
+			StringPtrField
+			BoolField
+			InterfaceField
 		}
 		SliceStructField {
-			StringsSlice 
-			IntsSlice 
-			StringPtrsSlice 
-			BoolsSlice 
+			StringsSlice
+			IntsSlice
+			StringPtrsSlice
+			BoolsSlice
 		}
 	}
 }`,
@@ -474,29 +555,29 @@ func Test_ParseToGQLQuery(t *testing.T) {
 			name: "slices of pointers struct with nil slices",
 			data: slicesOfPointersStruct{SimpleStructPtrs: nil, EmbeddedStructPtrs: nil},
 			expectedQuery: `{
-	name 
+	name
 	simpleStructPtrs {
-		StringField 
-		IntField 
-		StringPtrField 
-		BoolField 
-		InterfaceField 
+		StringField
+		IntField
+		StringPtrField
+		BoolField
+		InterfaceField
 	}
 	embeddedStructPtrs {
-		StringField 
-		IntField 
+		StringField
+		IntField
 		SimpleStructField {
-			StringField 
-			IntField 
-			StringPtrField 
-			BoolField 
-			InterfaceField 
+			StringField
+			IntField
+			StringPtrField
+			BoolField
+			InterfaceField
 		}
 		SliceStructField {
-			StringsSlice 
-			IntsSlice 
-			StringPtrsSlice 
-			BoolsSlice 
+			StringsSlice
+			IntsSlice
+			StringPtrsSlice
+			BoolsSlice
 		}
 	}
 }`,
@@ -505,13 +586,47 @@ func Test_ParseToGQLQuery(t *testing.T) {
 			name: "maps struct",
 			data: mapsStruct{},
 			expectedQuery: `{
-	SimpleMap 
-	FloatMap 
-	StructMap 
-	InterfaceMap 
-	PointersMap 
-	MapAlias 
-	NilMapPlaceholder 
+	SimpleMap
+	FloatMap
+	StructMap
+	InterfaceMap
+	PointersMap
+	MapAlias
+	NilMapPlaceholder
+}`,
+		},
+		{
+			name: "embedded structs with conflicting fields",
+			data: embeddingStruct{},
+			expectedQuery: `{
+	name
+	description
+	id
+}`,
+		},
+		{
+			name: "embedded structs with overridden fields",
+			data: &overridingStruct{embeddingStruct: embeddingStruct{describedStruct: &describedStruct{}}},
+			expectedQuery: `{
+	name
+	description
+	id
+	kind
+	extra {
+		stringField
+		intField
+		sliceField
+	}
+}`,
+		},
+		{
+			name: "embedded structs with tags",
+			data: taggedEmbeddingStruct{},
+			expectedQuery: `{
+	Title
+	named {
+		name
+	}
 }`,
 		},
 		{
@@ -521,10 +636,105 @@ func Test_ParseToGQLQuery(t *testing.T) {
 		},
 	} {
 		t.Run(testCase.name, func(t *testing.T) {
-			query := ParseToGQLQuery(testCase.data)
+			query, err := ParseToGQLQuery(testCase.data)
+			require.NoError(t, err)
 			t.Log(query)
 			assert.Equal(t, testCase.expectedQuery, query)
 		})
 	}
 
+	t.Run("unexported fields", func(t *testing.T) {
+		query, err := ParseToGQLQuery(unexportedFieldsStruct{internal: "test"})
+		require.NoError(t, err)
+		assert.Equal(t, `{
+	name
+	named {
+		name
+		kind
+	}
+	description
+	kind
+}`, query)
+	})
+
+	for _, testCase := range []struct {
+		name          string
+		data          interface{}
+		expectedError string
+	}{
+		{
+			name:          "func",
+			data:          struct{ Callback func() }{},
+			expectedError: "failed to parse Callback field: unsupported type func()",
+		},
+		{
+			name:          "slice of channels",
+			data:          &struct{ Events []chan string }{},
+			expectedError: "failed to parse Events field: unsupported type chan string",
+		},
+		{
+			name: "nested complex",
+			data: struct {
+				Inner []*struct{ Complex complex128 }
+			}{},
+			expectedError: "failed to parse Inner field: failed to parse Complex field: unsupported type complex128",
+		},
+		{
+			name:          "channel",
+			data:          make(chan int),
+			expectedError: "unsupported type chan int",
+		},
+	} {
+		t.Run("should return error for unsupported type: "+testCase.name, func(t *testing.T) {
+			query, err := ParseToGQLQuery(testCase.data)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.expectedError)
+			assert.Empty(t, query)
+		})
+	}
+
+	t.Run("should not select fields of marshaled structs", func(t *testing.T) {
+		query, err := ParseToGQLQuery(event{})
+		require.NoError(t, err)
+		assert.Equal(t, `{
+	name
+	createdAt
+	amount
+}`, query)
+	})
+
+	t.Run("should return error for struct without exported fields", func(t *testing.T) {
+		query, err := ParseToGQLQuery(struct{ Inner noExportedFieldsStruct }{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse Inner field: unsupported type graphql.noExportedFieldsStruct, it has no exported fields")
+		assert.Empty(t, query)
+	})
+
+	t.Run("include type name", func(t *testing.T) {
+		query, err := ParseToGQLQuery([]*typeNamedStruct{}, ParserOptions{IncludeTypeName: true})
+		require.NoError(t, err)
+		assert.Equal(t, `{
+	__typename
+	name
+	inner {
+		stringField
+		intField
+		sliceField
+		__typename
+	}
+}`, query)
+	})
+
+	t.Run("embedded type name without include type name option", func(t *testing.T) {
+		query, err := ParseToGQLQuery(typeNamedStruct{}, ParserOptions{Compact: true})
+		require.NoError(t, err)
+		assert.Equal(t, `{__typename name inner{stringField intField sliceField}}`, query)
+	})
+
+	t.Run("compact json-tagged complex struct", func(t *testing.T) {
+		query, err := ParseToGQLQuery(jsonTaggedComplexStruct{}, ParserOptions{Compact: true})
+		require.NoError(t, err)
+		assert.Equal(t, `{stringField intField jsonTaggedStruct{stringField intField sliceField} jsonTaggedStructs{stringField intField sliceField}}`, query)
+	})
+
 }
@@ -0,0 +1,251 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RetryAfterHeader = "Retry-After"
+
+	// retryAfterExtension is the extension of GraphQL errors with the number of seconds to wait before the next request
+	retryAfterExtension = "retryAfter"
+)
+
+// rateLimitErrorCodes are GraphQL error codes, from extensions.code, used by servers to report exceeded rate limits
+var rateLimitErrorCodes = []string{"RATE_LIMITED", "THROTTLED"}
+
+// limiter limits the rate and concurrency of requests and pauses them when the server reports exceeded rate limit
+type limiter struct {
+	bucket *tokenBucket
+	slots  chan struct{}
+
+	mutex       sync.Mutex
+	pausedUntil time.Time
+}
+
+func (o *options) initLimits() *limiter {
+	if o.limits == nil {
+		o.limits = &limiter{}
+	}
+
+	return o.limits
+}
+
+// acquire waits until the request can be sent, returning the function that has to be called after it is finished
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+			release = func() { <-l.slots }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if err := sleep(ctx, l.pauseRemaining()); err != nil {
+		release()
+		return nil, err
+	}
+
+	if l.bucket != nil {
+		if err := l.bucket.wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// pause delays all requests for the duration
+func (l *limiter) pause(duration time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if until := time.Now().Add(duration); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+func (l *limiter) pauseRemaining() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return time.Until(l.pausedUntil)
+}
+
+// tokenBucket allows requests at the rate, with bursts of up to the size of the bucket
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mutex  sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes the token from the bucket, returning the time after which it is available
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns the reserved token to the bucket
+func (b *tokenBucket) cancel() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// wait takes the token from the bucket, waiting until it is available or the context is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve(time.Now())
+	if err := sleep(ctx, delay); err != nil {
+		b.cancel()
+		return err
+	}
+
+	return nil
+}
+
+// acquireLimits waits until the HTTP request can be sent according to the limits of the client,
+// returning the function that has to be called after the response is read
+func (c Client) acquireLimits(ctx context.Context) (func(), error) {
+	if c.options.limits == nil {
+		return func() {}, nil
+	}
+
+	return c.options.limits.acquire(ctx)
+}
+
+// releasingBody releases limits acquired for the request when the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+
+	return err
+}
+
+// withLimits wraps the handler to pause requests for the time requested by the server when it reports exceeded rate limit.
+// Rate and concurrency limits are applied to each HTTP request when it is sent.
+func (c Client) withLimits(handler Handler) Handler {
+	if c.options.limits == nil {
+		return handler
+	}
+	limits := c.options.limits
+
+	return func(ctx context.Context, request Request, responseOut interface{}) (*Response, error) {
+		response, err := handler(ctx, request, responseOut)
+		if delay, ok := rateLimitDelay(response, err); ok {
+			c.log(ctx, LogLevelWarn, "Rate limit exceeded, pausing requests", LogField{Key: LogFieldBackoff, Value: delay})
+			limits.pause(delay)
+		}
+
+		return response, err
+	}
+}
+
+// rateLimitDelay returns the time to wait before the next request if the request failed because of exceeded rate limit
+// and the server specified it with Retry-After header or retryAfter extension of the GraphQL error
+func rateLimitDelay(response *Response, err error) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	var gqlErrs GraphQLErrors
+	errors.As(err, &gqlErrs)
+
+	rateLimited := false
+	for _, code := range rateLimitErrorCodes {
+		rateLimited = rateLimited || gqlErrs.HasCode(code)
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		rateLimited = rateLimited || httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode == http.StatusServiceUnavailable
+	}
+
+	if !rateLimited {
+		return 0, false
+	}
+
+	if response != nil {
+		if delay, ok := parseRetryAfter(response.Header.Get(RetryAfterHeader), time.Now()); ok {
+			return delay, true
+		}
+	}
+
+	for _, gqlErr := range gqlErrs {
+		if seconds, ok := gqlErr.Extensions[retryAfterExtension].(float64); ok && seconds >= 0 {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+	}
+
+	return 0, false
+}
+
+// parseRetryAfter parses the value of Retry-After header, which is either the number of seconds or HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := date.Sub(now); delay > 0 {
+		return delay, true
+	}
+
+	return 0, true
+}
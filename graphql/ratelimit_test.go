@@ -0,0 +1,106 @@
+package graphql
+
+import (
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(10, 2)
+	bucket.last = now
+
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, bucket.reserve(now))
+
+	bucket.cancel()
+	assert.Equal(t, time.Duration(0), bucket.reserve(now.Add(200*time.Millisecond)))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now.Add(time.Second)))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now.Add(time.Second)))
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve(now.Add(time.Second)), "should not accumulate more tokens than burst")
+}
+
+func TestWithRateLimit(t *testing.T) {
+	for _, rate := range []float64{0, -1, math.NaN()} {
+		o := &options{}
+		WithRateLimit(rate, 1).apply(o)
+
+		assert.Nil(t, o.limits, "should ignore rate %v", rate)
+	}
+
+	o := &options{}
+	WithRateLimit(10, 1).apply(o)
+	require.NotNil(t, o.limits)
+	assert.NotNil(t, o.limits.bucket)
+}
+
+func TestWithMaxConcurrency(t *testing.T) {
+	for _, maxConcurrency := range []int{0, -1} {
+		o := &options{}
+		WithMaxConcurrency(maxConcurrency).apply(o)
+
+		assert.Nil(t, o.limits, "should ignore max concurrency %d", maxConcurrency)
+	}
+
+	o := &options{}
+	WithMaxConcurrency(2).apply(o)
+	require.NotNil(t, o.limits)
+	assert.Equal(t, 2, cap(o.limits.slots))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, testCase := range []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{value: "", ok: false},
+		{value: "120", expected: 2 * time.Minute, ok: true},
+		{value: "-1", ok: false},
+		{value: "Wed, 01 Jan 2020 12:00:30 GMT", expected: 30 * time.Second, ok: true},
+		{value: "Wed, 01 Jan 2020 11:00:00 GMT", expected: 0, ok: true},
+		{value: "soon", ok: false},
+	} {
+		t.Run(testCase.value, func(t *testing.T) {
+			delay, ok := parseRetryAfter(testCase.value, now)
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.expected, delay)
+		})
+	}
+}
+
+func TestRateLimitDelay(t *testing.T) {
+	retryAfter := &Response{Header: http.Header{RetryAfterHeader: {"2"}}}
+	rateLimitedErr := GraphQLErrors{{Message: "rate limited", Extensions: map[string]interface{}{"code": "RATE_LIMITED", "retryAfter": 0.5}}}
+
+	for _, testCase := range []struct {
+		description string
+		response    *Response
+		err         error
+		expected    time.Duration
+		ok          bool
+	}{
+		{description: "no error", response: retryAfter, err: nil, ok: false},
+		{description: "too many requests with Retry-After", response: retryAfter, err: &HTTPError{StatusCode: http.StatusTooManyRequests}, expected: 2 * time.Second, ok: true},
+		{description: "too many requests without Retry-After", response: &Response{}, err: &HTTPError{StatusCode: http.StatusTooManyRequests}, ok: false},
+		{description: "other status with Retry-After", response: retryAfter, err: &HTTPError{StatusCode: http.StatusBadGateway}, ok: false},
+		{description: "rate limit error code with extension", response: &Response{}, err: rateLimitedErr, expected: 500 * time.Millisecond, ok: true},
+		{description: "rate limit error code with Retry-After", response: retryAfter, err: rateLimitedErr, expected: 2 * time.Second, ok: true},
+		{description: "other error code", response: retryAfter, err: GraphQLErrors{{Message: "not found"}}, ok: false},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			delay, ok := rateLimitDelay(testCase.response, testCase.err)
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.expected, delay)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactedValue replaces redacted values in logs
+const RedactedValue = "[REDACTED]"
+
+// RedactionPolicy defines how sensitive data is removed from requests and responses before they are logged
+type RedactionPolicy struct {
+	// Headers are names of headers which values are redacted
+	Headers []string
+	// Variables are dot separated paths of values which are redacted, such as in.password.
+	// Paths are matched against variables of the request as well as against arguments inlined in the query,
+	// with elements of lists matched by the path of the list.
+	// Values cannot be redacted from the query if they are inlined as a part of a string, for example in a raw query
+	// built by the caller with string formatting.
+	Variables []string
+	// MaxBodyLength is the maximum number of bytes of the response body that is logged, 0 does not limit the length
+	MaxBodyLength int
+}
+
+// DefaultRedactionPolicy redacts headers with credentials and limits the length of logged bodies
+var DefaultRedactionPolicy = RedactionPolicy{
+	Headers:       []string{AuthorizationHeader, "Proxy-Authorization", "Cookie", "Set-Cookie"},
+	MaxBodyLength: 4096,
+}
+
+func (p RedactionPolicy) redactHeaders(header http.Header) http.Header {
+	if len(p.Headers) == 0 || len(header) == 0 {
+		return header
+	}
+
+	// Header names are matched regardless of case, as headers passed by the caller may not be canonicalized
+	redacted := header.Clone()
+	for name := range redacted {
+		for _, redactedName := range p.Headers {
+			if strings.EqualFold(name, redactedName) {
+				redacted[name] = []string{RedactedValue}
+				break
+			}
+		}
+	}
+
+	return redacted
+}
+
+func (p RedactionPolicy) redactVariables(variables map[string]interface{}) interface{} {
+	if len(p.Variables) == 0 || len(variables) == 0 {
+		return variables
+	}
+
+	// Variables are converted to JSON representation, as they may contain structs
+	encoded, err := json.Marshal(variables)
+	if err != nil {
+		return RedactedValue
+	}
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return RedactedValue
+	}
+
+	return redactValue(decoded, "", p.variablePaths())
+}
+
+func redactValue(value interface{}, path string, paths map[string]bool) interface{} {
+	if paths[path] {
+		return RedactedValue
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, fieldValue := range typed {
+			typed[key] = redactValue(fieldValue, joinPath(path, key), paths)
+		}
+	case []interface{}:
+		for i, element := range typed {
+			typed[i] = redactValue(element, path, paths)
+		}
+	}
+
+	return value
+}
+
+// redactQuery redacts values of arguments inlined in the query
+func (p RedactionPolicy) redactQuery(query string) string {
+	if len(p.Variables) == 0 {
+		return query
+	}
+
+	redactor := &queryRedactor{
+		tokens: tokenize(query),
+		paths:  p.variablePaths(),
+	}
+	for redactor.pos < len(redactor.tokens) {
+		if redactor.next() == "(" {
+			redactor.arguments()
+		}
+	}
+
+	return strings.Join(redactor.redacted, " ")
+}
+
+func (p RedactionPolicy) variablePaths() map[string]bool {
+	paths := make(map[string]bool, len(p.Variables))
+	for _, path := range p.Variables {
+		paths[path] = true
+	}
+
+	return paths
+}
+
+// truncate shortens the body to the maximum length, not splitting UTF-8 characters
+func (p RedactionPolicy) truncate(body string) string {
+	if p.MaxBodyLength <= 0 || len(body) <= p.MaxBodyLength {
+		return body
+	}
+
+	end := p.MaxBodyLength
+	for end > 0 && !utf8.RuneStart(body[end]) {
+		end--
+	}
+
+	return body[:end] + "... (truncated)"
+}
+
+// queryRedactor copies tokens of the query replacing values of arguments matching the paths
+type queryRedactor struct {
+	tokens   []string
+	pos      int
+	paths    map[string]bool
+	redacted []string
+}
+
+func (r *queryRedactor) peek(offset int) string {
+	if r.pos+offset < len(r.tokens) {
+		return r.tokens[r.pos+offset]
+	}
+	return ""
+}
+
+// next copies the current token and moves to the next one
+func (r *queryRedactor) next() string {
+	token := r.tokens[r.pos]
+	r.redacted = append(r.redacted, token)
+	r.pos++
+
+	return token
+}
+
+// arguments copies arguments until the closing parenthesis
+func (r *queryRedactor) arguments() {
+	for r.pos < len(r.tokens) {
+		switch {
+		case r.peek(0) == ")":
+			r.next()
+			return
+		case r.peek(0) == "$":
+			// Variable definitions are copied without changes
+			r.next()
+			if r.pos < len(r.tokens) {
+				r.next()
+			}
+		case isNameToken(r.peek(0)) && r.peek(1) == ":":
+			name := r.next()
+			r.next()
+			r.value(name)
+		default:
+			r.next()
+		}
+	}
+}
+
+// value copies the value at the path, or replaces it if the path is redacted
+func (r *queryRedactor) value(path string) {
+	if r.pos >= len(r.tokens) {
+		return
+	}
+
+	if r.paths[path] && r.peek(0) != "$" {
+		r.redacted = append(r.redacted, `"`+RedactedValue+`"`)
+		r.skipValue()
+		return
+	}
+
+	switch r.next() {
+	case "{":
+		for r.pos < len(r.tokens) {
+			switch {
+			case r.peek(0) == "}":
+				r.next()
+				return
+			case isNameToken(r.peek(0)) && r.peek(1) == ":":
+				name := r.next()
+				r.next()
+				r.value(joinPath(path, name))
+			default:
+				r.next()
+			}
+		}
+	case "[":
+		for r.pos < len(r.tokens) && r.peek(0) != "]" {
+			r.value(path)
+		}
+		if r.pos < len(r.tokens) {
+			r.next()
+		}
+	case "$":
+		if r.pos < len(r.tokens) {
+			r.next()
+		}
+	}
+}
+
+// skipValue moves past the current value including nested objects and lists
+func (r *queryRedactor) skipValue() {
+	depth := 0
+	for r.pos < len(r.tokens) {
+		token := r.tokens[r.pos]
+		r.pos++
+
+		switch token {
+		case "{", "[":
+			depth++
+		case "}", "]":
+			depth--
+		}
+		if depth <= 0 {
+			return
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,116 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactionPolicy_RedactHeaders(t *testing.T) {
+	policy := RedactionPolicy{Headers: []string{"authorization", "X-Api-Key"}}
+	header := http.Header{
+		"Authorization": {"Bearer token"},
+		"X-Api-Key":     {"key-1", "key-2"},
+		"Test":          {"value"},
+	}
+
+	redacted := policy.redactHeaders(header)
+
+	assert.Equal(t, http.Header{
+		"Authorization": {RedactedValue},
+		"X-Api-Key":     {RedactedValue},
+		"Test":          {"value"},
+	}, redacted)
+	assert.Equal(t, "Bearer token", header.Get("Authorization"), "should not modify request headers")
+
+	t.Run("should redact headers with not canonical names", func(t *testing.T) {
+		header := http.Header{
+			"authorization": {"Bearer token"},
+			"x-api-key":     {"key"},
+		}
+
+		redacted := DefaultRedactionPolicy.redactHeaders(header)
+
+		assert.Equal(t, http.Header{
+			"authorization": {RedactedValue},
+			"x-api-key":     {"key"},
+		}, redacted)
+	})
+}
+
+func TestRedactionPolicy_RedactVariables(t *testing.T) {
+	type credentials struct {
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}
+
+	policy := RedactionPolicy{Variables: []string{"in.password", "in.dogs.secret", "token"}}
+	variables := map[string]interface{}{
+		"in": map[string]interface{}{
+			"name":     "Ted",
+			"password": "secret",
+			"dogs":     []interface{}{map[string]interface{}{"name": "Rex", "secret": "bone"}},
+		},
+		"token": credentials{Login: "ted", Password: "secret"},
+		"id":    1,
+	}
+
+	redacted := policy.redactVariables(variables)
+
+	encoded, err := json.Marshal(redacted)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"in": {"name": "Ted", "password": "[REDACTED]", "dogs": [{"name": "Rex", "secret": "[REDACTED]"}]},
+		"token": "[REDACTED]",
+		"id": 1
+	}`, string(encoded))
+	assert.Equal(t, "secret", variables["in"].(map[string]interface{})["password"], "should not modify request variables")
+}
+
+func TestRedactionPolicy_RedactQuery(t *testing.T) {
+	policy := RedactionPolicy{Variables: []string{"in.password", "in.dogs.secret", "token"}}
+
+	for _, testCase := range []struct {
+		description string
+		query       string
+		expected    string
+	}{
+		{
+			description: "should redact inlined input fields",
+			query:       `mutation { result: createHuman(in: {name: "Ted", password: "secret", dogs: [{name: "Rex", secret: "bone"}, {secret: "ball"}]}) { id } }`,
+			expected:    `mutation { result: createHuman(in: {name: "Ted", password: "[REDACTED]", dogs: [{name: "Rex", secret: "[REDACTED]"}, {secret: "[REDACTED]"}]}) { id } }`,
+		},
+		{
+			description: "should redact whole objects",
+			query:       `query { login(token: {value: "secret", nested: [1, 2]}, id: 1) { id } }`,
+			expected:    `query { login(token: "[REDACTED]", id: 1) { id } }`,
+		},
+		{
+			description: "should not redact variable references",
+			query:       `mutation Create($token: String!) { login(token: $token) { id } }`,
+			expected:    `mutation Create($token: String!) { login(token: $token) { id } }`,
+		},
+		{
+			description: "should not redact output fields",
+			query:       `query { human(id: 1) { password token } }`,
+			expected:    `query { human(id: 1) { password token } }`,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert.Equal(t, PrettyPrint(testCase.expected), PrettyPrint(policy.redactQuery(testCase.query)))
+		})
+	}
+}
+
+func TestRedactionPolicy_Truncate(t *testing.T) {
+	policy := RedactionPolicy{MaxBodyLength: 5}
+
+	assert.Equal(t, "short", policy.truncate("short"))
+	assert.Equal(t, "longe... (truncated)", policy.truncate("longer body"))
+	assert.Equal(t, "zaż... (truncated)", policy.truncate("zażółć"), "should not split characters")
+	assert.Equal(t, strings.Repeat("a", 100), RedactionPolicy{}.truncate(strings.Repeat("a", 100)))
+}
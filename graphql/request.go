@@ -5,11 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/textproto"
+	"net/url"
 )
 
 const (
 	ContentTypeHeader = "Content-Type"
 	AcceptHeader      = "Accept"
+
+	// MediaTypeGraphQLResponse is the media type of responses defined by the GraphQL over HTTP specification
+	MediaTypeGraphQLResponse = "application/graphql-response+json"
+	// MediaTypeJSON is the media type of responses of servers not supporting the GraphQL over HTTP specification
+	MediaTypeJSON = "application/json"
+
+	requestContentType = MediaTypeJSON + "; charset=utf-8"
+	// acceptedMediaTypes prefers the GraphQL response media type, while allowing legacy JSON responses
+	acceptedMediaTypes = MediaTypeGraphQLResponse + ", " + MediaTypeJSON + ";q=0.9"
 )
 
 var (
@@ -17,11 +28,28 @@ var (
 )
 
 type Request struct {
-	Query  string
-	Header http.Header
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+	Extensions    map[string]interface{}
+	Header        http.Header
+
+	// Idempotent marks mutation as safe to be retried
+	Idempotent bool
+
+	// operation is the type of the operation used when it cannot be determined from the Query,
+	// for example when only the hash of the persisted query is sent
+	operation OperationType
+	// incremental observes payloads of the response if the request uses incremental delivery
+	incremental IncrementalObserver
 
 	// TODO: files
-	// TODO: vars
+}
+
+// isIdempotent determines if the request can be safely sent again, which is the case for queries
+// and mutations marked as idempotent
+func (r Request) isIdempotent() bool {
+	return r.OperationType() != Mutation || r.Idempotent
 }
 
 func NewRequestRaw(query string, header ...http.Header) Request {
@@ -42,8 +70,9 @@ func newRequest(operation Operation, parserOpts ParserOptions, headers ...http.H
 	}
 
 	return Request{
-		Query:  query,
-		Header: mergeHeaders(headers),
+		Query:      query,
+		Header:     mergeHeaders(headers),
+		Idempotent: operation.Idempotent,
 	}, nil
 }
 
@@ -60,21 +89,135 @@ func (r Request) ToHttpRequest(endpoint string) (*http.Request, error) {
 		return nil, fmt.Errorf("failed to create http request: %w", err)
 	}
 
-	httpRequest.Header = r.Header
-	r.Header.Set(ContentTypeHeader, "application/json; charset=utf-8")
-	r.Header.Set(AcceptHeader, "application/json; charset=utf-8")
+	// Header is cloned, so that headers set for the single attempt, like Content-Encoding, do not leak to the next ones
+	httpRequest.Header = r.Header.Clone()
+	if httpRequest.Header == nil {
+		httpRequest.Header = http.Header{}
+	}
+	httpRequest.Header.Set(ContentTypeHeader, requestContentType)
+	httpRequest.Header.Set(AcceptHeader, acceptedMediaTypes)
 
 	return httpRequest, nil
 }
 
+// ToHttpGetRequest creates GET request with the parameters of the GraphQL request encoded in the URL query,
+// following GraphQL over HTTP specification. GET requests should be used only for queries.
+func (r Request) ToHttpGetRequest(endpoint string) (*http.Request, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse endpoint URL: %w", err)
+	}
+
+	params := endpointURL.Query()
+	if r.Query != "" {
+		params.Set("query", r.Query)
+	}
+	if r.OperationName != "" {
+		params.Set("operationName", r.OperationName)
+	}
+	if err := setJSONParam(params, "variables", r.Variables); err != nil {
+		return nil, err
+	}
+	if err := setJSONParam(params, "extensions", r.Extensions); err != nil {
+		return nil, err
+	}
+	endpointURL.RawQuery = params.Encode()
+
+	httpRequest, err := http.NewRequest(http.MethodGet, endpointURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http request: %w", err)
+	}
+
+	httpRequest.Header = r.Header.Clone()
+	if httpRequest.Header == nil {
+		httpRequest.Header = http.Header{}
+	}
+	httpRequest.Header.Del(ContentTypeHeader)
+	httpRequest.Header.Set(AcceptHeader, acceptedMediaTypes)
+
+	return httpRequest, nil
+}
+
+func setJSONParam(params url.Values, name string, value map[string]interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	params.Set(name, string(encoded))
+
+	return nil
+}
+
 func (r Request) toRequestData() gqlRequestData {
 	return gqlRequestData{
 		Query:         r.Query,
-		OperationName: "",
-		Variables:     nil,
+		OperationName: r.OperationName,
+		Variables:     r.Variables,
+		Extensions:    r.Extensions,
+	}
+}
+
+// OperationType determines the type of the executed operation.
+// If the document contains multiple operations the one matching the OperationName is used.
+func (r Request) OperationType() OperationType {
+	if r.operation != "" {
+		return r.operation
+	}
+
+	depth := 0
+	tokens := tokenize(r.Query)
+
+	for i, token := range tokens {
+		switch token {
+		case "{":
+			if depth == 0 && r.OperationName == "" {
+				// Query shorthand
+				return Query
+			}
+			depth++
+		case "}":
+			depth--
+		case string(Query), string(Mutation), string(Subscription):
+			if depth != 0 {
+				continue
+			}
+			if r.OperationName == "" || (i+1 < len(tokens) && tokens[i+1] == r.OperationName) {
+				return OperationType(token)
+			}
+		}
 	}
+
+	return Query
 }
 
+// ResolvedOperationName returns the name of the executed operation, which is the OperationName if set
+// or the name of the first operation in the document
+func (r Request) ResolvedOperationName() string {
+	if r.OperationName != "" {
+		return r.OperationName
+	}
+
+	tokens := tokenize(r.Query)
+	for i, token := range tokens {
+		switch token {
+		case "{":
+			return ""
+		case string(Query), string(Mutation), string(Subscription):
+			if i+1 < len(tokens) && isNameToken(tokens[i+1]) {
+				return tokens[i+1]
+			}
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// mergeHeaders merges headers appending values of the same header
 func mergeHeaders(headers []http.Header) http.Header {
 	mergedHeaders := http.Header{}
 
@@ -86,3 +229,17 @@ func mergeHeaders(headers []http.Header) http.Header {
 
 	return mergedHeaders
 }
+
+// overrideHeaders merges headers in such a way that values of the header
+// replace the values of the same header from the headers preceding it
+func overrideHeaders(headers ...http.Header) http.Header {
+	overriddenHeaders := http.Header{}
+
+	for _, header := range headers {
+		for h, v := range header {
+			overriddenHeaders[textproto.CanonicalMIMEHeaderKey(h)] = append([]string(nil), v...)
+		}
+	}
+
+	return overriddenHeaders
+}
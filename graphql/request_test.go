@@ -0,0 +1,71 @@
+package graphql
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_OperationType(t *testing.T) {
+	for _, testCase := range []struct {
+		request       Request
+		operationType OperationType
+	}{
+		{request: Request{Query: "{ dogs { id } }"}, operationType: Query},
+		{request: Request{Query: "query { mutation }"}, operationType: Query},
+		{request: Request{Query: "# comment\nmutation Create { createDog { id } }"}, operationType: Mutation},
+		{request: Request{Query: "query Get { dogs { id } } mutation Create { createDog { id } }", OperationName: "Create"}, operationType: Mutation},
+		{request: Request{Query: "mutation Create { createDog { id } } query Get { dogs { id } }", OperationName: "Get"}, operationType: Query},
+		{request: Request{Query: "subscription { dogs { id } }"}, operationType: Subscription},
+	} {
+		t.Run(testCase.request.Query, func(t *testing.T) {
+			assert.Equal(t, testCase.operationType, testCase.request.OperationType())
+		})
+	}
+}
+
+func TestRequest_ResolvedOperationName(t *testing.T) {
+	for _, testCase := range []struct {
+		request       Request
+		operationName string
+	}{
+		{request: Request{Query: "{ dogs { id } }"}, operationName: ""},
+		{request: Request{Query: "query { dogs { id } }"}, operationName: ""},
+		{request: Request{Query: "query Dogs { dogs { id } }"}, operationName: "Dogs"},
+		{request: Request{Query: "mutation Create($in: DogInput!) { createDog(in: $in) { id } }"}, operationName: "Create"},
+		{request: Request{Query: "query Get { dogs { id } } mutation Create { createDog { id } }", OperationName: "Create"}, operationName: "Create"},
+	} {
+		t.Run(testCase.request.Query, func(t *testing.T) {
+			assert.Equal(t, testCase.operationName, testCase.request.ResolvedOperationName())
+		})
+	}
+}
+
+func TestRequest_ToHttpGetRequest(t *testing.T) {
+	request := Request{
+		Query:         "query Dog($id: ID!) { dog(id: $id) { name } }",
+		OperationName: "Dog",
+		Variables:     map[string]interface{}{"id": "abcd"},
+		Extensions:    map[string]interface{}{"key": "value"},
+		Header:        http.Header{"Test": {"test"}, ContentTypeHeader: {"application/json"}},
+	}
+
+	httpRequest, err := request.ToHttpGetRequest("http://localhost:8080/graphql?param=1")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodGet, httpRequest.Method)
+	assert.Equal(t, "/graphql", httpRequest.URL.Path)
+
+	params := httpRequest.URL.Query()
+	assert.Equal(t, "1", params.Get("param"))
+	assert.Equal(t, request.Query, params.Get("query"))
+	assert.Equal(t, "Dog", params.Get("operationName"))
+	assert.Equal(t, `{"id":"abcd"}`, params.Get("variables"))
+	assert.Equal(t, `{"key":"value"}`, params.Get("extensions"))
+
+	assert.Equal(t, "test", httpRequest.Header.Get("Test"))
+	assert.Empty(t, httpRequest.Header.Get(ContentTypeHeader))
+	assert.Equal(t, "application/json", request.Header.Get(ContentTypeHeader))
+}
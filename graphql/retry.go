@@ -0,0 +1,145 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryPolicy determines if and when failed requests should be retried.
+// Mutations are retried only if they are marked as idempotent.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one
+	MaxAttempts int
+	// InitialBackoff is the time to wait before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff limits the time to wait between attempts
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the backoff is increased after each attempt
+	Multiplier float64
+	// Jitter is the fraction of the backoff by which it is randomly increased or decreased, between 0 and 1
+	Jitter float64
+
+	// RetryableStatusCodes are HTTP response status codes for which requests should be retried
+	RetryableStatusCodes []int
+	// RetryNetworkErrors determines if requests should be retried on errors such as connection reset or timeout
+	RetryNetworkErrors bool
+	// RetryableErrorCodes are GraphQL error codes, from extensions.code, for which requests should be retried
+	RetryableErrorCodes []string
+}
+
+// DefaultRetryPolicy retries requests failed with network errors or with gateway errors up to 3 times
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+	RetryableStatusCodes: []int{
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+	RetryNetworkErrors: true,
+}
+
+func (p RetryPolicy) isRetryable(ctx context.Context, request Request, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if !request.isIdempotent() {
+		return false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		for _, code := range p.RetryableStatusCodes {
+			if httpErr.StatusCode == code {
+				return true
+			}
+		}
+	}
+
+	var gqlErrs GraphQLErrors
+	if errors.As(err, &gqlErrs) {
+		for _, code := range p.RetryableErrorCodes {
+			if gqlErrs.HasCode(code) {
+				return true
+			}
+		}
+	}
+
+	var urlErr *url.Error
+	if p.RetryNetworkErrors && errors.As(err, &urlErr) {
+		return true
+	}
+
+	return false
+}
+
+// backoff returns the time to wait before the next attempt, attempts are counted from 1
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		backoff = backoff * (1 - p.Jitter + 2*p.Jitter*rand.Float64())
+	}
+
+	return time.Duration(backoff)
+}
+
+// withRetry wraps the handler to retry failed requests according to the retry policy
+func (c Client) withRetry(handler Handler) Handler {
+	if c.options.retryPolicy == nil {
+		return handler
+	}
+	policy := *c.options.retryPolicy
+
+	return func(ctx context.Context, request Request, responseOut interface{}) (*Response, error) {
+		for attempt := 1; ; attempt++ {
+			response, err := handler(ctx, request, responseOut)
+			if err == nil || attempt >= policy.MaxAttempts || !policy.isRetryable(ctx, request, err) {
+				return response, err
+			}
+
+			backoff := policy.backoff(attempt)
+			if delay, ok := rateLimitDelay(response, err); ok && delay > backoff {
+				backoff = delay
+			}
+			c.log(ctx, LogLevelWarn, "Retrying request",
+				LogField{Key: LogFieldAttempt, Value: attempt},
+				LogField{Key: LogFieldBackoff, Value: backoff},
+				LogField{Key: LogFieldError, Value: err.Error()},
+			)
+
+			if err := sleep(ctx, backoff); err != nil {
+				return response, err
+			}
+			recorderFromContext(ctx).retry()
+		}
+	}
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, time.Second, policy.backoff(10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		backoff := policy.backoff(2)
+		assert.True(t, backoff >= 100*time.Millisecond && backoff <= 300*time.Millisecond)
+	}
+}
+
+func TestRetryPolicy_IsRetryable(t *testing.T) {
+	policy := DefaultRetryPolicy
+	policy.RetryableErrorCodes = []string{"UNAVAILABLE"}
+
+	query := Request{Query: "query { result: dogs { id } }"}
+	mutation := Request{Query: "mutation { result: createDog { id } }"}
+	idempotentMutation := Request{Query: "mutation { result: createDog { id } }", Idempotent: true}
+
+	networkErr := &url.Error{Op: "Post", URL: "http://localhost", Err: errors.New("connection reset by peer")}
+	gatewayErr := &HTTPError{StatusCode: http.StatusBadGateway}
+	unavailableErr := GraphQLErrors{{Message: "unavailable", Extensions: map[string]interface{}{"code": "UNAVAILABLE"}}}
+
+	for _, testCase := range []struct {
+		description string
+		request     Request
+		err         error
+		retryable   bool
+	}{
+		{description: "network error", request: query, err: networkErr, retryable: true},
+		{description: "wrapped network error", request: query, err: fmt.Errorf("error while executing request: %w", networkErr), retryable: true},
+		{description: "retryable status code", request: query, err: gatewayErr, retryable: true},
+		{description: "non retryable status code", request: query, err: &HTTPError{StatusCode: http.StatusBadRequest}, retryable: false},
+		{description: "retryable error code", request: query, err: unavailableErr, retryable: true},
+		{description: "retryable error code in HTTP error", request: query, err: &HTTPError{StatusCode: http.StatusBadRequest, Errors: unavailableErr}, retryable: true},
+		{description: "non retryable error code", request: query, err: GraphQLErrors{{Message: "not found"}}, retryable: false},
+		{description: "mutation", request: mutation, err: gatewayErr, retryable: false},
+		{description: "idempotent mutation", request: idempotentMutation, err: gatewayErr, retryable: true},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert.Equal(t, testCase.retryable, policy.isRetryable(context.Background(), testCase.request, testCase.err))
+		})
+	}
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.False(t, policy.isRetryable(ctx, query, networkErr))
+	})
+}
@@ -0,0 +1,123 @@
+// Package tracing provides OpenTelemetry tracing of GraphQL operations executed by graphql.Client
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+const instrumentationName = "github.com/szymongib/graphql-client/graphql/tracing"
+
+// Attributes of the spans
+const (
+	OperationTypeKey     = attribute.Key("graphql.operation.type")
+	OperationNameKey     = attribute.Key("graphql.operation.name")
+	DocumentHashKey      = attribute.Key("graphql.document.hash")
+	ErrorCountKey        = attribute.Key("graphql.error.count")
+	ResponseStatusKey    = attribute.Key("http.response.status_code")
+	ResponseMediaTypeKey = attribute.Key("http.response.media_type")
+)
+
+type options struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// Option configures the tracing middleware
+type Option func(*options)
+
+// WithTracerProvider sets the provider of the tracer creating spans, the global one is used by default
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator injecting trace context to request headers, the global one is used by default
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(o *options) {
+		o.propagator = propagator
+	}
+}
+
+// Middleware returns graphql.Middleware creating span for every executed operation.
+// Trace context is propagated to the server with request headers and errors are recorded on the span.
+// The middleware should be the first one, so that the span covers retries and other middlewares.
+func Middleware(opts ...Option) graphql.Middleware {
+	o := options{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	tracer := o.tracerProvider.Tracer(instrumentationName)
+
+	return func(next graphql.Handler) graphql.Handler {
+		return func(ctx context.Context, request graphql.Request, responseOut interface{}) (*graphql.Response, error) {
+			operationType := string(request.OperationType())
+			operationName := request.ResolvedOperationName()
+
+			ctx, span := tracer.Start(ctx, spanName(operationType, operationName),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					OperationTypeKey.String(operationType),
+					OperationNameKey.String(operationName),
+					DocumentHashKey.String(documentHash(request.Query)),
+				),
+			)
+			defer span.End()
+
+			// Headers are copied to not modify headers passed by the caller
+			request.Header = request.Header.Clone()
+			if request.Header == nil {
+				request.Header = http.Header{}
+			}
+			o.propagator.Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+			response, err := next(ctx, request, responseOut)
+			if response != nil {
+				span.SetAttributes(
+					ResponseStatusKey.Int(response.StatusCode),
+					ResponseMediaTypeKey.String(response.MediaType),
+				)
+			}
+
+			if err != nil {
+				var gqlErrs graphql.GraphQLErrors
+				errors.As(err, &gqlErrs)
+
+				span.SetAttributes(ErrorCountKey.Int(len(gqlErrs)))
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			return response, err
+		}
+	}
+}
+
+// spanName follows OpenTelemetry semantic conventions for GraphQL
+func spanName(operationType, operationName string) string {
+	if operationName == "" {
+		return operationType
+	}
+
+	return operationType + " " + operationName
+}
+
+func documentHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,161 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Run("should create span for the operation and propagate trace context", func(t *testing.T) {
+		var traceParent string
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			traceParent = request.Header.Get("Traceparent")
+			writer.Header().Set("Content-Type", "application/json")
+			_, _ = writer.Write([]byte(`{"data":{"dogs":[{"id":"1"}]}}`))
+		}))
+		defer server.Close()
+
+		exporter, client := newTracedClient(server.URL)
+
+		header := http.Header{"Test": {"value"}}
+		var response interface{}
+		err := client.Execute(context.Background(), graphql.NewRequestRaw("query Dogs { dogs { id } }", header), &response)
+		require.NoError(t, err)
+
+		spans := exporter.GetSpans()
+		require.Equal(t, 1, len(spans))
+		span := spans[0]
+
+		assert.Equal(t, "query Dogs", span.Name)
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+		assert.Equal(t, codes.Unset, span.Status.Code)
+		assert.Contains(t, span.Attributes, OperationTypeKey.String("query"))
+		assert.Contains(t, span.Attributes, OperationNameKey.String("Dogs"))
+		assert.Contains(t, span.Attributes, DocumentHashKey.String(documentHash("query Dogs { dogs { id } }")))
+		assert.Contains(t, span.Attributes, ResponseStatusKey.Int(http.StatusOK))
+
+		assert.Contains(t, traceParent, span.SpanContext.TraceID().String())
+		assert.Empty(t, header.Get("Traceparent"), "should not modify headers passed by the caller")
+	})
+
+	t.Run("should record errors on the span", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Content-Type", "application/json")
+			_, _ = writer.Write([]byte(`{"data":null,"errors":[{"message":"first"},{"message":"second"}]}`))
+		}))
+		defer server.Close()
+
+		exporter, client := newTracedClient(server.URL)
+
+		var response interface{}
+		err := client.Execute(context.Background(), graphql.NewRequestRaw("mutation { createDog { id } }"), &response)
+		require.Error(t, err)
+
+		spans := exporter.GetSpans()
+		require.Equal(t, 1, len(spans))
+		span := spans[0]
+
+		assert.Equal(t, "mutation", span.Name)
+		assert.Equal(t, codes.Error, span.Status.Code)
+		assert.Contains(t, span.Attributes, ErrorCountKey.Int(2))
+		require.Equal(t, 1, len(span.Events))
+		assert.Equal(t, "exception", span.Events[0].Name)
+	})
+
+	t.Run("should send traced operations in batches with trace context of the first operation", func(t *testing.T) {
+		var (
+			mutex       sync.Mutex
+			batches     int
+			traceParent string
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			mutex.Lock()
+			batches++
+			traceParent = request.Header.Get("Traceparent")
+			mutex.Unlock()
+
+			writer.Header().Set("Content-Type", "application/json")
+			_, _ = writer.Write([]byte(`[{"data":{"dogs":[]}},{"data":{"dogs":[]}}]`))
+		}))
+		defer server.Close()
+
+		var transportSpan trace.SpanContext
+		httpClient := &http.Client{Transport: roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			mutex.Lock()
+			transportSpan = trace.SpanContextFromContext(request.Context())
+			mutex.Unlock()
+
+			return http.DefaultTransport.RoundTrip(request)
+		})}
+
+		exporter := tracetest.NewInMemoryExporter()
+		client := graphql.NewClient(server.URL,
+			graphql.WithHTTPClient(httpClient),
+			graphql.WithBatching(time.Second, 2),
+			graphql.WithMiddleware(Middleware(
+				WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))),
+				WithPropagator(propagation.TraceContext{}),
+			)),
+		)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				var response interface{}
+				err := client.Execute(context.Background(), graphql.NewRequestRaw("query { dogs { id } }"), &response)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, batches)
+
+		spans := exporter.GetSpans()
+		require.Equal(t, 2, len(spans))
+		require.True(t, transportSpan.IsValid(), "should pass the span to the transport")
+
+		var batchSpan *tracetest.SpanStub
+		for i := range spans {
+			if spans[i].SpanContext.SpanID() == transportSpan.SpanID() {
+				batchSpan = &spans[i]
+			}
+		}
+		require.NotNil(t, batchSpan, "transport span should be the span of one of the operations")
+		assert.Contains(t, traceParent, batchSpan.SpanContext.SpanID().String())
+	})
+}
+
+type roundTripperFunc func(request *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func newTracedClient(endpoint string) (*tracetest.InMemoryExporter, *graphql.Client) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	client := graphql.NewClient(endpoint, graphql.WithMiddleware(Middleware(
+		WithTracerProvider(provider),
+		WithPropagator(propagation.TraceContext{}),
+	)))
+
+	return exporter, client
+}
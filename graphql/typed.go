@@ -0,0 +1,62 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+)
+
+// Result holds data of the operation decoded to T and details of the response
+type Result[T any] struct {
+	Data T
+	// Response is nil if no response was received
+	Response *Response
+}
+
+// RunAs executes GraphQL operation returning the result decoded to T.
+// If operation.Requested is not set, the selection set is derived from T.
+func RunAs[T any](ctx context.Context, c *Client, operation Operation, header ...http.Header) (T, error) {
+	result, err := RunAsWithResponse[T](ctx, c, operation, header...)
+	return result.Data, err
+}
+
+// RunAsWithResponse executes GraphQL operation as RunAs does, returning the result together with the Response
+func RunAsWithResponse[T any](ctx context.Context, c *Client, operation Operation, header ...http.Header) (Result[T], error) {
+	var result Result[T]
+	if operation.Requested == nil {
+		operation.Requested = &result.Data
+	}
+
+	response, err := c.RunWithResponse(ctx, operation, &result.Data, header...)
+	result.Response = response
+	return result, err
+}
+
+// QueryAs executes GraphQL query with provided input, deriving the selection set from T and returning the decoded result
+func QueryAs[T any](ctx context.Context, c *Client, name string, input OperationInput, header ...http.Header) (T, error) {
+	result, err := QueryAsWithResponse[T](ctx, c, name, input, header...)
+	return result.Data, err
+}
+
+// QueryAsWithResponse executes GraphQL query as QueryAs does, returning the result together with the Response
+func QueryAsWithResponse[T any](ctx context.Context, c *Client, name string, input OperationInput, header ...http.Header) (Result[T], error) {
+	return RunAsWithResponse[T](ctx, c, Operation{
+		Type:  Query,
+		Name:  name,
+		Input: input,
+	}, header...)
+}
+
+// MutateAs executes GraphQL mutation with provided input, deriving the selection set from T and returning the decoded result
+func MutateAs[T any](ctx context.Context, c *Client, name string, input OperationInput, header ...http.Header) (T, error) {
+	result, err := MutateAsWithResponse[T](ctx, c, name, input, header...)
+	return result.Data, err
+}
+
+// MutateAsWithResponse executes GraphQL mutation as MutateAs does, returning the result together with the Response
+func MutateAsWithResponse[T any](ctx context.Context, c *Client, name string, input OperationInput, header ...http.Header) (Result[T], error) {
+	return RunAsWithResponse[T](ctx, c, Operation{
+		Type:  Mutation,
+		Name:  name,
+		Input: input,
+	}, header...)
+}
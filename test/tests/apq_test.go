@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_AutomaticPersistedQueries(t *testing.T) {
+	defer resolver.ResetData()
+
+	dogID := uuid.New().String()
+	resolver.DogsDb = []*schema.Dog{{ID: dogID, Name: "Rex"}}
+
+	t.Run("should register query and then send only its hash", func(t *testing.T) {
+		api := newPersistedQueriesAPI()
+		defer api.Close()
+		server, requests := newPersistedQueriesRecordingServer(t, api.URL)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithAutomaticPersistedQueries())
+
+		for i := 0; i < 2; i++ {
+			var dog schema.Dog
+			err := client.Query(context.Background(), "dog", graphql.OperationInput{"id": dogID}, &dog)
+			require.NoError(t, err)
+			assert.Equal(t, "Rex", dog.Name)
+		}
+
+		assert.Equal(t, []recordedRequest{
+			{Method: http.MethodPost, WithHash: true},
+			{Method: http.MethodPost, WithHash: true, WithQuery: true},
+			{Method: http.MethodPost, WithHash: true},
+		}, requests())
+	})
+
+	t.Run("should send hashes with GET requests", func(t *testing.T) {
+		api := newPersistedQueriesAPI()
+		defer api.Close()
+		server, requests := newPersistedQueriesRecordingServer(t, api.URL)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithAutomaticPersistedQueries(), graphql.WithGETForQueries(0))
+
+		for i := 0; i < 2; i++ {
+			var dogs []*schema.Dog
+			err := client.Query(context.Background(), "dogs", nil, &dogs)
+			require.NoError(t, err)
+			assert.Equal(t, 1, len(dogs))
+		}
+
+		var human schema.Human
+		err := client.Mutate(context.Background(), "createHuman", graphql.OperationInput{"in": humanInput("Ted", nil)}, &human)
+		require.NoError(t, err)
+		assert.Equal(t, "Ted", human.Name)
+
+		assert.Equal(t, []recordedRequest{
+			{Method: http.MethodGet, WithHash: true},
+			{Method: http.MethodGet, WithHash: true, WithQuery: true},
+			{Method: http.MethodGet, WithHash: true},
+			{Method: http.MethodPost, WithHash: true},
+			{Method: http.MethodPost, WithHash: true, WithQuery: true},
+		}, requests())
+	})
+
+	t.Run("should fallback to full queries if server does not support persisted queries", func(t *testing.T) {
+		server, requests := newPersistedQueriesRecordingServer(t, apiAddress)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithAutomaticPersistedQueries())
+
+		for i := 0; i < 2; i++ {
+			var dogs []*schema.Dog
+			err := client.Query(context.Background(), "dogs", nil, &dogs)
+			require.NoError(t, err)
+			assert.Equal(t, 1, len(dogs))
+		}
+
+		assert.Equal(t, []recordedRequest{
+			{Method: http.MethodPost, WithHash: true},
+			{Method: http.MethodPost, WithQuery: true},
+			{Method: http.MethodPost, WithQuery: true},
+		}, requests())
+	})
+}
+
+type recordedRequest struct {
+	Method    string
+	WithQuery bool
+	WithHash  bool
+}
+
+// newPersistedQueriesRecordingServer returns server proxying requests to the address
+// and recording whether they contained the query and the persisted query hash.
+// Requests with the hash only should not contain the query parameter at all.
+func newPersistedQueriesRecordingServer(t *testing.T, address string) (*httptest.Server, func() []recordedRequest) {
+	return newRecordingProxyTo(t, address, func(request *http.Request) (recordedRequest, proxyHandler) {
+		params := map[string]json.RawMessage{}
+
+		if request.Method == http.MethodGet {
+			for name, values := range request.URL.Query() {
+				params[name] = json.RawMessage(values[0])
+			}
+		} else {
+			body, err := ioutil.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &params))
+			request.Body = ioutil.NopCloser(strings.NewReader(string(body)))
+		}
+
+		_, withQuery := params["query"]
+		return recordedRequest{
+			Method:    request.Method,
+			WithQuery: withQuery,
+			WithHash:  strings.Contains(string(params["extensions"]), "sha256Hash"),
+		}, nil
+	})
+}
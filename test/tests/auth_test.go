@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_Auth(t *testing.T) {
+	t.Run("should authenticate requests with cached token", func(t *testing.T) {
+		server := newAuthServer(t, "Bearer token-1", http.StatusUnauthorized)
+		defer server.Close()
+
+		tokenSource, fetches := newCountingTokenSource(time.Time{})
+		client := graphql.NewClient(server.URL, graphql.WithTokenSource(tokenSource))
+
+		for i := 0; i < 3; i++ {
+			var dogs []*schema.Dog
+			err := client.Query(context.Background(), "dogs", nil, &dogs)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(fetches))
+	})
+
+	t.Run("should fetch new token when cached one expired", func(t *testing.T) {
+		server := newAuthServer(t, "", http.StatusUnauthorized)
+		defer server.Close()
+
+		tokenSource, fetches := newCountingTokenSource(time.Now().Add(time.Second))
+		client := graphql.NewClient(server.URL, graphql.WithTokenSource(tokenSource))
+
+		for i := 0; i < 2; i++ {
+			var dogs []*schema.Dog
+			err := client.Query(context.Background(), "dogs", nil, &dogs)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(fetches))
+	})
+
+	t.Run("should refresh token when server responds with 401", func(t *testing.T) {
+		server := newAuthServer(t, "Bearer token-2", http.StatusUnauthorized)
+		defer server.Close()
+
+		tokenSource, fetches := newCountingTokenSource(time.Time{})
+		client := graphql.NewClient(server.URL, graphql.WithTokenSource(tokenSource))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(fetches))
+	})
+
+	t.Run("should refresh token when server responds with UNAUTHENTICATED error", func(t *testing.T) {
+		server := newAuthServer(t, "Bearer token-2", http.StatusOK)
+		defer server.Close()
+
+		tokenSource, fetches := newCountingTokenSource(time.Time{})
+		client := graphql.NewClient(server.URL, graphql.WithTokenSource(tokenSource))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(fetches))
+	})
+
+	t.Run("should refresh token only once", func(t *testing.T) {
+		server := newAuthServer(t, "Bearer token-3", http.StatusUnauthorized)
+		defer server.Close()
+
+		tokenSource, fetches := newCountingTokenSource(time.Time{})
+		client := graphql.NewClient(server.URL, graphql.WithTokenSource(tokenSource))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(fetches))
+
+		var httpErr *graphql.HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusUnauthorized, httpErr.StatusCode)
+	})
+
+	t.Run("should return error if token source fails", func(t *testing.T) {
+		client := graphql.NewClient(apiAddress, graphql.WithTokenSource(graphql.TokenSourceFunc(func(ctx context.Context) (*graphql.Token, error) {
+			return nil, assert.AnError
+		})))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get authentication token")
+	})
+}
+
+// newCountingTokenSource returns token source providing subsequent tokens: token-1, token-2...
+func newCountingTokenSource(expiry time.Time) (graphql.TokenSource, *int32) {
+	var fetches int32
+
+	return graphql.TokenSourceFunc(func(ctx context.Context) (*graphql.Token, error) {
+		return &graphql.Token{
+			AccessToken: fmt.Sprintf("token-%d", atomic.AddInt32(&fetches, 1)),
+			Expiry:      expiry,
+		}, nil
+	}), &fetches
+}
+
+// newAuthServer returns server proxying requests to the test API only if they are authorized with expected value.
+// Unauthorized requests are rejected with the status. If expected authorization is empty all requests are accepted.
+func newAuthServer(t *testing.T, expectedAuthorization string, rejectStatus int) *httptest.Server {
+	server, _ := newRecordingProxy(t, func(request *http.Request) (string, proxyHandler) {
+		authorization := request.Header.Get("Authorization")
+		if expectedAuthorization == "" || authorization == expectedAuthorization {
+			return authorization, nil
+		}
+
+		return authorization, func(writer http.ResponseWriter, request *http.Request, proxy http.Handler) {
+			writer.Header().Set("Content-Type", "application/json")
+			writer.WriteHeader(rejectStatus)
+			_, _ = writer.Write([]byte(`{"errors":[{"message":"invalid token","extensions":{"code":"UNAUTHENTICATED"}}],"data":null}`))
+		}
+	})
+
+	return server
+}
@@ -0,0 +1,224 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_Batching(t *testing.T) {
+	defer resolver.ResetData()
+
+	dogIDs := []string{uuid.New().String(), uuid.New().String(), uuid.New().String(), uuid.New().String()}
+	dogNames := []string{"Rex", "Max", "Azor", "Burek"}
+	resolver.DogsDb = nil
+	for i, id := range dogIDs {
+		resolver.DogsDb = append(resolver.DogsDb, &schema.Dog{ID: id, Name: dogNames[i]})
+	}
+
+	t.Run("should send concurrent operations in one batch", func(t *testing.T) {
+		server, batchSizes := newBatchingServer(t)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithBatching(100*time.Millisecond, 10))
+
+		dogs := make([]schema.Dog, len(dogIDs))
+		errs := make([]error, len(dogIDs))
+
+		var wg sync.WaitGroup
+		for i := range dogIDs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = client.Query(context.Background(), "dog", graphql.OperationInput{"id": dogIDs[i]}, &dogs[i])
+			}(i)
+		}
+		wg.Wait()
+
+		for i := range dogIDs {
+			require.NoError(t, errs[i])
+			assert.Equal(t, dogNames[i], dogs[i].Name)
+		}
+		assert.Equal(t, []int{len(dogIDs)}, batchSizes())
+	})
+
+	t.Run("should send batch when max size is reached", func(t *testing.T) {
+		server, batchSizes := newBatchingServer(t)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithBatching(time.Minute, 2))
+
+		var wg sync.WaitGroup
+		for i := range dogIDs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				var dog schema.Dog
+				err := client.Query(context.Background(), "dog", graphql.OperationInput{"id": dogIDs[i]}, &dog)
+				assert.NoError(t, err)
+				assert.Equal(t, dogNames[i], dog.Name)
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, []int{2, 2}, batchSizes())
+	})
+
+	t.Run("should return errors to operations that failed", func(t *testing.T) {
+		server, batchSizes := newBatchingServer(t)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithBatching(100*time.Millisecond, 2))
+
+		var (
+			wg       sync.WaitGroup
+			dog      schema.Dog
+			dogErr   error
+			response string
+			queryErr error
+		)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			dogErr = client.Query(context.Background(), "dog", graphql.OperationInput{"id": dogIDs[0]}, &dog)
+		}()
+		go func() {
+			defer wg.Done()
+			queryErr = client.Query(context.Background(), "errorsQuery", nil, &response)
+		}()
+		wg.Wait()
+
+		require.NoError(t, dogErr)
+		assert.Equal(t, dogNames[0], dog.Name)
+
+		require.Error(t, queryErr)
+		var gqlErrs graphql.GraphQLErrors
+		require.True(t, errors.As(queryErr, &gqlErrs))
+		assert.Contains(t, queryErr.Error(), "error you requested")
+
+		assert.Equal(t, []int{2}, batchSizes())
+	})
+
+	t.Run("should not send cancelled operations", func(t *testing.T) {
+		server, batchSizes := newBatchingServer(t)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithBatching(200*time.Millisecond, 10))
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(dogIDs))
+		for i := range dogIDs {
+			operationCtx := context.Background()
+			if i == 0 {
+				operationCtx = ctx
+			}
+
+			wg.Add(1)
+			go func(ctx context.Context, i int) {
+				defer wg.Done()
+				var dog schema.Dog
+				errs[i] = client.Query(ctx, "dog", graphql.OperationInput{"id": dogIDs[i]}, &dog)
+			}(operationCtx, i)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+		wg.Wait()
+
+		assert.Equal(t, context.Canceled, errs[0])
+		for _, err := range errs[1:] {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, []int{len(dogIDs) - 1}, batchSizes())
+	})
+
+	t.Run("should send single operation as regular request", func(t *testing.T) {
+		server, batchSizes := newBatchingServer(t)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithBatching(10*time.Millisecond, 10))
+
+		var dog schema.Dog
+		err := client.Query(context.Background(), "dog", graphql.OperationInput{"id": dogIDs[0]}, &dog)
+		require.NoError(t, err)
+		assert.Equal(t, dogNames[0], dog.Name)
+
+		assert.Equal(t, []int{0}, batchSizes())
+	})
+
+	t.Run("should batch only operations with the same headers", func(t *testing.T) {
+		server, batchSizes := newBatchingServer(t)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithBatching(100*time.Millisecond, 10))
+
+		var wg sync.WaitGroup
+		for i := range dogIDs {
+			header := http.Header{"X-Group": []string{"a"}}
+			if i == 0 {
+				header = http.Header{"X-Group": []string{"b"}}
+			}
+
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				var dog schema.Dog
+				err := client.Query(context.Background(), "dog", graphql.OperationInput{"id": dogIDs[i]}, &dog, header)
+				assert.NoError(t, err)
+				assert.Equal(t, dogNames[i], dog.Name)
+			}(i)
+		}
+		wg.Wait()
+
+		assert.ElementsMatch(t, []int{0, len(dogIDs) - 1}, batchSizes())
+	})
+}
+
+// newBatchingServer returns server executing batches of operations against the test API one by one.
+// Sizes of received batches are recorded, with single, not batched requests recorded as 0.
+func newBatchingServer(t *testing.T) (*httptest.Server, func() []int) {
+	return newRecordingProxy(t, func(request *http.Request) (int, proxyHandler) {
+		body, err := ioutil.ReadAll(request.Body)
+		require.NoError(t, err)
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		var operations []json.RawMessage
+		if err := json.Unmarshal(body, &operations); err != nil {
+			// Operation sent as a regular request is recorded as a batch of size 0
+			return 0, nil
+		}
+
+		return len(operations), func(writer http.ResponseWriter, request *http.Request, proxy http.Handler) {
+			results := make([]json.RawMessage, 0, len(operations))
+			for _, operation := range operations {
+				res, err := http.Post(apiAddress, "application/json", bytes.NewReader(operation))
+				require.NoError(t, err)
+				result, err := ioutil.ReadAll(res.Body)
+				require.NoError(t, err)
+				_ = res.Body.Close()
+
+				results = append(results, result)
+			}
+
+			writer.Header().Set("Content-Type", "application/json")
+			require.NoError(t, json.NewEncoder(writer).Encode(results))
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_CircuitBreaker(t *testing.T) {
+	defer resolver.ResetData()
+
+	t.Run("should reject requests when circuit is open until server recovers", func(t *testing.T) {
+		server, requests := newFlakyServer(t, 2, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		var (
+			mutex       sync.Mutex
+			transitions []string
+		)
+		client := graphql.NewClient(server.URL, graphql.WithCircuitBreaker(graphql.CircuitBreakerPolicy{
+			FailureThreshold:    2,
+			OpenTimeout:         100 * time.Millisecond,
+			HalfOpenMaxRequests: 1,
+			OnStateChange: func(from, to graphql.CircuitState) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				transitions = append(transitions, from.String()+" -> "+to.String())
+			},
+		}))
+
+		var dogs []*schema.Dog
+		for i := 0; i < 2; i++ {
+			err := client.Query(context.Background(), "dogs", nil, &dogs)
+			require.Error(t, err)
+		}
+
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+		assert.Equal(t, graphql.ErrCircuitOpen, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(requests), "should not send request when circuit is open")
+
+		time.Sleep(100 * time.Millisecond)
+
+		err = client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		assert.Equal(t, []string{"closed -> open", "open -> half-open", "half-open -> closed"}, transitions)
+	})
+
+	t.Run("should not count GraphQL errors as failures", func(t *testing.T) {
+		client := graphql.NewClient(apiAddress, graphql.WithCircuitBreaker(graphql.CircuitBreakerPolicy{
+			FailureThreshold: 1,
+			OpenTimeout:      time.Minute,
+		}))
+
+		for i := 0; i < 2; i++ {
+			var response string
+			err := client.Query(context.Background(), "errorsQuery", nil, &response)
+			require.Error(t, err)
+			assert.NotEqual(t, graphql.ErrCircuitOpen, err)
+		}
+	})
+}
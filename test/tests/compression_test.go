@@ -0,0 +1,250 @@
+package tests
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_Compression(t *testing.T) {
+	defer resolver.ResetData()
+
+	t.Run("should compress request body larger than threshold", func(t *testing.T) {
+		for _, codec := range []graphql.Codec{graphql.GzipCodec, graphql.DeflateCodec, flateCodec{}} {
+			t.Run(codec.Encoding(), func(t *testing.T) {
+				server, encodings := newCompressionServer(t, "")
+				defer server.Close()
+
+				client := graphql.NewClient(server.URL, graphql.WithRequestCompression(codec, 10))
+
+				var human schema.Human
+				err := client.Mutate(context.Background(), "createHuman", graphql.OperationInput{"in": humanInput("Ted", nil)}, &human)
+				require.NoError(t, err)
+				assert.Equal(t, "Ted", human.Name)
+
+				assert.Equal(t, []string{codec.Encoding()}, encodings())
+			})
+		}
+	})
+
+	t.Run("should not compress request body smaller than threshold", func(t *testing.T) {
+		server, encodings := newCompressionServer(t, "")
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithRequestCompression(graphql.GzipCodec, 1<<20))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{""}, encodings())
+	})
+
+	t.Run("should not send Content-Encoding of previous attempts with retried requests", func(t *testing.T) {
+		dogID := uuid.New().String()
+		resolver.DogsDb = []*schema.Dog{{ID: dogID, Name: "Rex"}}
+
+		type compressedRequest struct {
+			Encoding  string
+			WithQuery bool
+		}
+
+		api := newPersistedQueriesAPI()
+		defer api.Close()
+
+		var failed int32
+		server, requests := newRecordingProxyTo(t, api.URL, func(request *http.Request) (compressedRequest, proxyHandler) {
+			encoding := request.Header.Get("Content-Encoding")
+			decompressRequest(t, request)
+
+			body, err := ioutil.ReadAll(request.Body)
+			require.NoError(t, err)
+			request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			recorded := compressedRequest{Encoding: encoding, WithQuery: strings.Contains(string(body), `"query":`)}
+			// The first request with the full query fails, so that the operation is retried
+			if !recorded.WithQuery || !atomic.CompareAndSwapInt32(&failed, 0, 1) {
+				return recorded, nil
+			}
+
+			return recorded, func(writer http.ResponseWriter, request *http.Request, proxy http.Handler) {
+				writer.WriteHeader(http.StatusBadGateway)
+			}
+		})
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL,
+			graphql.WithAutomaticPersistedQueries(),
+			graphql.WithRequestCompression(graphql.GzipCodec, 300),
+			graphql.WithRetry(graphql.RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusBadGateway}}),
+		)
+
+		var dog schema.Dog
+		err := client.Query(context.Background(), "dog", graphql.OperationInput{"id": dogID}, &dog)
+		require.NoError(t, err)
+		assert.Equal(t, "Rex", dog.Name)
+
+		assert.Equal(t, []compressedRequest{
+			{Encoding: ""},
+			{Encoding: "gzip", WithQuery: true},
+			{Encoding: ""},
+			{Encoding: "gzip", WithQuery: true},
+		}, requests())
+	})
+
+	t.Run("should request and decompress compressed responses", func(t *testing.T) {
+		for _, codecs := range [][]graphql.Codec{nil, {graphql.DeflateCodec}, {flateCodec{}}} {
+			server, _ := newCompressionServer(t, "")
+
+			client := graphql.NewClient(server.URL, graphql.WithResponseCompression(codecs...))
+
+			var human schema.Human
+			err := client.Mutate(context.Background(), "createHuman", graphql.OperationInput{"in": humanInput("Ted", nil)}, &human)
+			require.NoError(t, err)
+			assert.Equal(t, "Ted", human.Name)
+
+			server.Close()
+		}
+	})
+
+	t.Run("should decompress response when transport does not", func(t *testing.T) {
+		server, _ := newCompressionServer(t, "gzip")
+		defer server.Close()
+
+		httpClient := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+		client := graphql.NewClient(server.URL, graphql.WithHTTPClient(httpClient))
+
+		var human schema.Human
+		err := client.Mutate(context.Background(), "createHuman", graphql.OperationInput{"in": humanInput("Ted", nil)}, &human)
+		require.NoError(t, err)
+		assert.Equal(t, "Ted", human.Name)
+	})
+
+	t.Run("should return error for unsupported response encoding", func(t *testing.T) {
+		server, _ := newCompressionServer(t, flateCodec{}.Encoding())
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL)
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported response content encoding")
+	})
+}
+
+// flateCodec is a custom codec using raw deflate format
+type flateCodec struct{}
+
+func (flateCodec) Encoding() string {
+	return "x-flate"
+}
+
+func (flateCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return flate.NewWriter(w, flate.DefaultCompression)
+}
+
+func (flateCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return flate.NewReader(r), nil
+}
+
+// newCompressionServer returns server decompressing request bodies before passing them to the test API,
+// and compressing responses with the first encoding accepted by the client.
+// If responseEncoding is set responses are always compressed with it.
+// Content-Encoding of received requests is recorded.
+func newCompressionServer(t *testing.T, responseEncoding string) (*httptest.Server, func() []string) {
+	return newRecordingProxy(t, func(request *http.Request) (string, proxyHandler) {
+		return request.Header.Get("Content-Encoding"), func(writer http.ResponseWriter, request *http.Request, proxy http.Handler) {
+			decompressRequest(t, request)
+
+			compressWith := responseEncoding
+			if compressWith == "" {
+				compressWith = strings.Split(request.Header.Get("Accept-Encoding"), ", ")[0]
+			}
+			// Accept-Encoding is removed so that the test API does not compress the response
+			request.Header.Del("Accept-Encoding")
+
+			recorder := httptest.NewRecorder()
+			proxy.ServeHTTP(recorder, request)
+
+			body := recorder.Body.Bytes()
+			if compressWith != "" {
+				var compressed bytes.Buffer
+				compressor, err := codecFor(compressWith).NewWriter(&compressed)
+				require.NoError(t, err)
+				_, err = compressor.Write(body)
+				require.NoError(t, err)
+				require.NoError(t, compressor.Close())
+
+				body = compressed.Bytes()
+				writer.Header().Set("Content-Encoding", compressWith)
+			}
+
+			writer.Header().Set("Content-Type", recorder.Header().Get("Content-Type"))
+			writer.WriteHeader(recorder.Code)
+			_, _ = writer.Write(body)
+		}
+	})
+}
+
+// decompressRequest replaces the compressed body of the request with the decompressed one
+func decompressRequest(t *testing.T, request *http.Request) {
+	encoding := request.Header.Get("Content-Encoding")
+	if encoding == "" {
+		return
+	}
+
+	reader, err := codecFor(encoding).NewReader(request.Body)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	request.ContentLength = int64(len(body))
+	request.Header.Del("Content-Encoding")
+}
+
+func codecFor(encoding string) graphql.Codec {
+	switch encoding {
+	case "gzip":
+		return testGzipCodec{}
+	case "deflate":
+		return testZlibCodec{}
+	default:
+		return flateCodec{}
+	}
+}
+
+// testGzipCodec and testZlibCodec are used by the test server independently of the codecs of the client
+type testGzipCodec struct{}
+
+func (testGzipCodec) Encoding() string { return "gzip" }
+
+func (testGzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }
+
+func (testGzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }
+
+type testZlibCodec struct{}
+
+func (testZlibCodec) Encoding() string { return "deflate" }
+
+func (testZlibCodec) NewWriter(w io.Writer) (io.WriteCloser, error) { return zlib.NewWriter(w), nil }
+
+func (testZlibCodec) NewReader(r io.Reader) (io.ReadCloser, error) { return zlib.NewReader(r) }
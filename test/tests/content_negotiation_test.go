@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_ContentNegotiation(t *testing.T) {
+	defer resolver.ResetData()
+
+	dogID := uuid.New().String()
+	resolver.DogsDb = []*schema.Dog{{ID: dogID, Name: "Rex"}}
+
+	t.Run("should accept GraphQL response media type", func(t *testing.T) {
+		var accept string
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			accept = request.Header.Get("Accept")
+			newAPIProxy(t).ServeHTTP(writer, request)
+		}))
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL)
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+		assert.Equal(t, "application/graphql-response+json, application/json;q=0.9", accept)
+	})
+
+	t.Run("should expose media type of the response", func(t *testing.T) {
+		server := newGraphQLOverHTTPServer(t)
+		defer server.Close()
+
+		for _, testCase := range []struct {
+			address   string
+			mediaType string
+		}{
+			{address: apiAddress, mediaType: graphql.MediaTypeJSON},
+			{address: server.URL, mediaType: graphql.MediaTypeGraphQLResponse},
+		} {
+			var mediaType string
+			client := graphql.NewClient(testCase.address, graphql.WithMiddleware(func(next graphql.Handler) graphql.Handler {
+				return func(ctx context.Context, request graphql.Request, responseOut interface{}) (*graphql.Response, error) {
+					response, err := next(ctx, request, responseOut)
+					if response != nil {
+						mediaType = response.MediaType
+					}
+					return response, err
+				}
+			}))
+
+			var dog schema.Dog
+			err := client.Query(context.Background(), "dog", graphql.OperationInput{"id": dogID}, &dog)
+			require.NoError(t, err)
+			assert.Equal(t, "Rex", dog.Name)
+			assert.Equal(t, testCase.mediaType, mediaType)
+		}
+	})
+
+	t.Run("should return GraphQL errors from 4xx response", func(t *testing.T) {
+		server := newGraphQLOverHTTPServer(t)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL)
+
+		var response interface{}
+		err := client.Execute(context.Background(), graphql.NewRequestRaw("query { notExistingField }"), &response)
+		require.Error(t, err)
+
+		var httpErr *graphql.HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+		assert.Equal(t, graphql.MediaTypeGraphQLResponse, httpErr.MediaType)
+		require.NotEmpty(t, httpErr.Errors)
+		assert.Contains(t, httpErr.Errors[0].Message, "notExistingField")
+
+		var gqlErrs graphql.GraphQLErrors
+		assert.True(t, errors.As(err, &gqlErrs))
+	})
+
+	t.Run("should not parse GraphQL errors from non JSON response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Content-Type", "text/html")
+			writer.WriteHeader(http.StatusBadGateway)
+			_, _ = writer.Write([]byte(`{"errors":[{"message":"not a GraphQL error"}]}`))
+		}))
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL)
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+
+		var httpErr *graphql.HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, "text/html", httpErr.MediaType)
+		assert.Empty(t, httpErr.Errors)
+		assert.Contains(t, err.Error(), "unexpected response status")
+	})
+}
+
+// newGraphQLOverHTTPServer returns server responding as defined by GraphQL over HTTP specification
+// with application/graphql-response+json media type, if accepted by the client.
+// Responses without data are treated as request errors and returned with 400 status.
+func newGraphQLOverHTTPServer(t *testing.T) *httptest.Server {
+	proxy := newAPIProxy(t)
+
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		recorder := httptest.NewRecorder()
+		proxy.ServeHTTP(recorder, request)
+
+		if !strings.Contains(request.Header.Get("Accept"), graphql.MediaTypeGraphQLResponse) {
+			writer.Header().Set("Content-Type", recorder.Header().Get("Content-Type"))
+			writer.WriteHeader(recorder.Code)
+			_, _ = writer.Write(recorder.Body.Bytes())
+			return
+		}
+
+		var response struct {
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+
+		status := recorder.Code
+		if len(response.Data) == 0 || string(response.Data) == "null" {
+			status = http.StatusBadRequest
+		}
+
+		writer.Header().Set("Content-Type", graphql.MediaTypeGraphQLResponse+"; charset=utf-8")
+		writer.WriteHeader(status)
+		_, _ = writer.Write(recorder.Body.Bytes())
+	}))
+}
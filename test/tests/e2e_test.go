@@ -19,9 +19,9 @@ func Test_E2e_Flow(t *testing.T) {
 	// given
 	defer resolver.ResetData()
 
-	logger := func(s string) {
-		assert.NotEmpty(t, s)
-	}
+	logger := graphql.LoggerFunc(func(ctx context.Context, level graphql.LogLevel, message string, fields ...graphql.LogField) {
+		assert.NotEmpty(t, message)
+	})
 
 	gqlClient := graphql.NewClient(apiAddress, graphql.WithLogger(logger))
 
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_Endpoints(t *testing.T) {
+	defer resolver.ResetData()
+
+	t.Run("should distribute requests between endpoints", func(t *testing.T) {
+		first, firstRequests := newFlakyServer(t, 0, http.StatusOK)
+		defer first.Close()
+		second, secondRequests := newFlakyServer(t, 0, http.StatusOK)
+		defer second.Close()
+
+		client := graphql.NewClient(first.URL, graphql.WithEndpoints(graphql.DefaultEndpointPolicy, second.URL))
+
+		for i := 0; i < 4; i++ {
+			var dogs []*schema.Dog
+			err := client.Query(context.Background(), "dogs", nil, &dogs)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(firstRequests))
+		assert.Equal(t, int32(2), atomic.LoadInt32(secondRequests))
+	})
+
+	t.Run("should fail over to the next endpoint and skip unhealthy one", func(t *testing.T) {
+		primary, primaryRequests := newFlakyServer(t, 100, http.StatusBadGateway)
+		defer primary.Close()
+		fallback, fallbackRequests := newFlakyServer(t, 0, http.StatusOK)
+		defer fallback.Close()
+
+		client := graphql.NewClient(primary.URL, graphql.WithEndpoints(graphql.EndpointPolicy{
+			Strategy:         graphql.PrimaryFallback,
+			FailureThreshold: 1,
+			UnhealthyTimeout: time.Minute,
+		}, fallback.URL))
+
+		for i := 0; i < 3; i++ {
+			var dogs []*schema.Dog
+			err := client.Query(context.Background(), "dogs", nil, &dogs)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(primaryRequests))
+		assert.Equal(t, int32(3), atomic.LoadInt32(fallbackRequests))
+	})
+
+	t.Run("should fail over when endpoint is unreachable", func(t *testing.T) {
+		unreachable := httptest.NewServer(http.NotFoundHandler())
+		unreachable.Close()
+		fallback, fallbackRequests := newFlakyServer(t, 0, http.StatusOK)
+		defer fallback.Close()
+
+		client := graphql.NewClient(unreachable.URL, graphql.WithEndpoints(graphql.EndpointPolicy{Strategy: graphql.PrimaryFallback}, fallback.URL))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(fallbackRequests))
+	})
+
+	t.Run("should not send mutation to the next endpoint", func(t *testing.T) {
+		primary, primaryRequests := newFlakyServer(t, 100, http.StatusBadGateway)
+		defer primary.Close()
+		fallback, fallbackRequests := newFlakyServer(t, 0, http.StatusOK)
+		defer fallback.Close()
+
+		client := graphql.NewClient(primary.URL, graphql.WithEndpoints(graphql.EndpointPolicy{
+			Strategy:         graphql.PrimaryFallback,
+			FailureThreshold: 1,
+			UnhealthyTimeout: time.Minute,
+		}, fallback.URL))
+
+		var human schema.Human
+		err := client.Mutate(context.Background(), "createHuman", graphql.OperationInput{"in": humanInput("Ted", nil)}, &human)
+		require.Error(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(primaryRequests))
+		assert.Equal(t, int32(0), atomic.LoadInt32(fallbackRequests))
+
+		err = client.Mutate(context.Background(), "createHuman", graphql.OperationInput{"in": humanInput("Ted", nil)}, &human)
+		require.NoError(t, err)
+		assert.Equal(t, "Ted", human.Name)
+		assert.Equal(t, int32(1), atomic.LoadInt32(fallbackRequests))
+	})
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_GETForQueries(t *testing.T) {
+	defer resolver.ResetData()
+
+	dogID := uuid.New().String()
+	resolver.DogsDb = []*schema.Dog{{ID: dogID, Name: "Rex"}}
+
+	t.Run("should send queries with GET", func(t *testing.T) {
+		server, methods := newMethodRecordingServer(t)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithGETForQueries(0))
+
+		var dog schema.Dog
+		err := client.Query(context.Background(), "dog", graphql.OperationInput{"id": dogID}, &dog)
+		require.NoError(t, err)
+		assert.Equal(t, "Rex", dog.Name)
+
+		var result struct {
+			Result schema.Dog `json:"result"`
+		}
+		err = client.Execute(context.Background(), graphql.Request{
+			Query:     "query DogQuery($id: ID!) { result: dog(id: $id) { id name } }",
+			Variables: map[string]interface{}{"id": dogID},
+		}, &result)
+		require.NoError(t, err)
+		assert.Equal(t, "Rex", result.Result.Name)
+
+		assert.Equal(t, []string{http.MethodGet, http.MethodGet}, methods())
+	})
+
+	t.Run("should send mutations with POST", func(t *testing.T) {
+		server, methods := newMethodRecordingServer(t)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithGETForQueries(0))
+
+		var human schema.Human
+		err := client.Mutate(context.Background(), "createHuman", graphql.OperationInput{"in": humanInput("Ted", nil)}, &human)
+		require.NoError(t, err)
+		assert.Equal(t, "Ted", human.Name)
+
+		assert.Equal(t, []string{http.MethodPost}, methods())
+	})
+
+	t.Run("should fallback to POST if URL is too long", func(t *testing.T) {
+		server, methods := newMethodRecordingServer(t)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithGETForQueries(100))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+		assert.Equal(t, 1, len(dogs))
+
+		assert.Equal(t, []string{http.MethodPost}, methods())
+	})
+}
+
+func newMethodRecordingServer(t *testing.T) (*httptest.Server, func() []string) {
+	return newRecordingProxy(t, func(request *http.Request) (string, proxyHandler) {
+		return request.Method, nil
+	})
+}
@@ -2,11 +2,11 @@ package tests
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"reflect"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -14,14 +14,13 @@ import (
 	"github.com/szymongib/graphql-client/util"
 
 	"github.com/szymongib/graphql-client/graphql"
+	"github.com/szymongib/graphql-client/graphql/logging"
 )
 
 func Test_Headers(t *testing.T) {
 	defer resolver.ResetData()
 
-	gqlClient := graphql.NewClient(apiAddress, graphql.WithLogger(func(s string) {
-		fmt.Println(s)
-	}))
+	gqlClient := graphql.NewClient(apiAddress, graphql.WithLogger(logging.Logrus(logrus.StandardLogger())))
 
 	headers := http.Header{
 		"Test":           {"val1", "val2"},
@@ -103,6 +102,61 @@ func Test_Headers(t *testing.T) {
 
 }
 
+type requestIDKey struct{}
+
+func Test_DefaultHeaders(t *testing.T) {
+	client := graphql.NewClient(apiAddress,
+		graphql.WithHeaders(http.Header{
+			"Static":   {"static"},
+			"Override": {"static"},
+		}),
+		graphql.WithHeaderProvider(func(ctx context.Context) (http.Header, error) {
+			return http.Header{
+				"Request-Id": {ctx.Value(requestIDKey{}).(string)},
+				"Override":   {"provider"},
+			}, nil
+		}),
+	)
+
+	t.Run("should add default headers to request", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), requestIDKey{}, "request-id")
+
+		var actualHeaders []*schema.Header
+		err := client.Query(ctx, "headersQuery", nil, &actualHeaders)
+		require.NoError(t, err)
+
+		assert.True(t, containsHeaders(actualHeaders, []*schema.Header{
+			{Name: "Static", Values: []*string{util.StringPtr("static")}},
+			{Name: "Request-Id", Values: []*string{util.StringPtr("request-id")}},
+			{Name: "Override", Values: []*string{util.StringPtr("provider")}},
+		}))
+	})
+
+	t.Run("should override default headers with request headers", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), requestIDKey{}, "request-id")
+
+		var actualHeaders []*schema.Header
+		err := client.Query(ctx, "headersQuery", nil, &actualHeaders, http.Header{"Override": {"request1"}}, http.Header{"Override": {"request2"}})
+		require.NoError(t, err)
+
+		assert.True(t, containsHeaders(actualHeaders, []*schema.Header{
+			{Name: "Static", Values: []*string{util.StringPtr("static")}},
+			{Name: "Override", Values: []*string{util.StringPtr("request1"), util.StringPtr("request2")}},
+		}))
+	})
+
+	t.Run("should return error if header provider fails", func(t *testing.T) {
+		client := graphql.NewClient(apiAddress, graphql.WithHeaderProvider(func(ctx context.Context) (http.Header, error) {
+			return nil, assert.AnError
+		}))
+
+		var actualHeaders []*schema.Header
+		err := client.Query(context.Background(), "headersQuery", nil, &actualHeaders)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get headers from provider")
+	})
+}
+
 func containsHeaders(all, contains []*schema.Header) bool {
 	for _, header := range contains {
 		if !containsHeader(header, all) {
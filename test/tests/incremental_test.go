@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+const incrementalHumanQuery = `query Human($id: ID!) {
+	human(id: $id) {
+		id
+		... @defer { name }
+		dogs @stream(initialCount: 1) { id name }
+	}
+}`
+
+func Test_IncrementalDelivery(t *testing.T) {
+	defer resolver.ResetData()
+
+	human := &schema.Human{
+		ID:   uuid.New().String(),
+		Name: "Ted",
+		Dogs: []*schema.Dog{
+			{ID: uuid.New().String(), Name: "Rex"},
+			{ID: uuid.New().String(), Name: "Max"},
+			{ID: uuid.New().String(), Name: "Azor"},
+		},
+	}
+	resolver.HumansDb = []*schema.Human{human}
+
+	request := graphql.Request{
+		Query:     incrementalHumanQuery,
+		Variables: map[string]interface{}{"id": human.ID},
+	}
+
+	t.Run("should apply incremental payloads to the result", func(t *testing.T) {
+		client := graphql.NewClient(incrementalAddress)
+
+		var (
+			response struct {
+				Human schema.Human `json:"human"`
+			}
+			observed []graphql.IncrementalResult
+			names    []string
+			dogs     []int
+		)
+		err := client.ExecuteIncremental(context.Background(), request, &response, func(result graphql.IncrementalResult) {
+			observed = append(observed, result)
+			names = append(names, response.Human.Name)
+			dogs = append(dogs, len(response.Human.Dogs))
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []graphql.IncrementalResult{
+			{Initial: true},
+			{},
+			{Completed: true},
+		}, observed)
+		assert.Equal(t, []string{"", "Ted", "Ted"}, names)
+		assert.Equal(t, []int{1, 1, 3}, dogs)
+
+		assert.Equal(t, human.ID, response.Human.ID)
+		assert.Equal(t, "Ted", response.Human.Name)
+		require.Equal(t, 3, len(response.Human.Dogs))
+		for i, dog := range human.Dogs {
+			assert.Equal(t, dog.Name, response.Human.Dogs[i].Name)
+		}
+	})
+
+	t.Run("should decode complete result if server does not support incremental delivery", func(t *testing.T) {
+		client := graphql.NewClient(apiAddress)
+
+		var (
+			response struct {
+				Human schema.Human `json:"human"`
+			}
+			observed []graphql.IncrementalResult
+		)
+		err := client.ExecuteIncremental(context.Background(), graphql.Request{
+			Query:     "query Human($id: ID!) { human(id: $id) { id name dogs { id name } } }",
+			Variables: map[string]interface{}{"id": human.ID},
+		}, &response, func(result graphql.IncrementalResult) {
+			observed = append(observed, result)
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []graphql.IncrementalResult{{Initial: true, Completed: true}}, observed)
+		assert.Equal(t, "Ted", response.Human.Name)
+		assert.Equal(t, 3, len(response.Human.Dogs))
+	})
+}
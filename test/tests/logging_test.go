@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_Logging(t *testing.T) {
+	defer resolver.ResetData()
+
+	t.Run("should log executed request with fields", func(t *testing.T) {
+		logger, entries := newRecordingLogger()
+		client := graphql.NewClient(apiAddress, graphql.WithLogger(logger))
+
+		var response struct {
+			Result []*schema.Dog `json:"result"`
+		}
+		err := client.Execute(context.Background(), graphql.NewRequestRaw("query Dogs { result: dogs { id } }"), &response)
+		require.NoError(t, err)
+
+		entry, found := findLogEntry(entries(), "Request executed")
+		require.True(t, found)
+		assert.Equal(t, graphql.LogLevelInfo, entry.level)
+		assert.Equal(t, "Dogs", entry.fields[graphql.LogFieldOperationName])
+		assert.Equal(t, "query", entry.fields[graphql.LogFieldOperationType])
+		assert.Equal(t, 200, entry.fields[graphql.LogFieldStatus])
+		assert.Equal(t, 0, entry.fields[graphql.LogFieldErrorCount])
+		assert.IsType(t, time.Duration(0), entry.fields[graphql.LogFieldDuration])
+
+		entry, found = findLogEntry(entries(), "Executing request")
+		require.True(t, found)
+		assert.Equal(t, graphql.LogLevelDebug, entry.level)
+		assert.Contains(t, entry.fields[graphql.LogFieldQuery], "result: dogs")
+	})
+
+	t.Run("should log failed request with error count", func(t *testing.T) {
+		logger, entries := newRecordingLogger()
+		client := graphql.NewClient(apiAddress, graphql.WithLogger(logger))
+
+		var response string
+		err := client.Query(context.Background(), "errorsQuery", nil, &response)
+		require.Error(t, err)
+
+		entry, found := findLogEntry(entries(), "Request failed")
+		require.True(t, found)
+		assert.Equal(t, graphql.LogLevelError, entry.level)
+		assert.Equal(t, 1, entry.fields[graphql.LogFieldErrorCount])
+		assert.Contains(t, entry.fields[graphql.LogFieldError], "error you requested")
+	})
+
+	t.Run("should redact sensitive data", func(t *testing.T) {
+		logger, entries := newRecordingLogger()
+		client := graphql.NewClient(apiAddress,
+			graphql.WithLogger(logger),
+			graphql.WithHeaders(http.Header{"Authorization": {"Bearer secret-token"}}),
+			graphql.WithRedaction(graphql.RedactionPolicy{
+				Headers:       []string{"Authorization"},
+				Variables:     []string{"in.name", "in.dogs.name"},
+				MaxBodyLength: 10,
+			}),
+		)
+
+		var human schema.Human
+		err := client.Mutate(context.Background(), "createHuman", graphql.OperationInput{"in": humanInput("Ted", []*schema.DogInput{dogInput("Rex", nil, nil)})}, &human)
+		require.NoError(t, err)
+		assert.Equal(t, "Ted", human.Name)
+
+		entry, found := findLogEntry(entries(), "Executing request")
+		require.True(t, found)
+		assert.Equal(t, []string{graphql.RedactedValue}, entry.fields[graphql.LogFieldHeaders].(http.Header)["Authorization"])
+		assert.NotContains(t, entry.fields[graphql.LogFieldQuery], "Ted")
+		assert.NotContains(t, entry.fields[graphql.LogFieldQuery], "Rex")
+		assert.Contains(t, entry.fields[graphql.LogFieldQuery], graphql.RedactedValue)
+
+		entry, found = findLogEntry(entries(), "Received response")
+		require.True(t, found)
+		assert.Equal(t, `{"data":{"... (truncated)`, entry.fields[graphql.LogFieldBody])
+	})
+
+	t.Run("should redact headers passed with not canonical names", func(t *testing.T) {
+		logger, entries := newRecordingLogger()
+		client := graphql.NewClient(apiAddress, graphql.WithLogger(logger))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs, http.Header{"authorization": {"Bearer secret-token"}})
+		require.NoError(t, err)
+
+		entry, found := findLogEntry(entries(), "Executing request")
+		require.True(t, found)
+		assert.Equal(t, []string{graphql.RedactedValue}, entry.fields[graphql.LogFieldHeaders].(http.Header)["authorization"])
+	})
+
+	t.Run("should log chunked response without changing decoding", func(t *testing.T) {
+		dogs := make([]map[string]interface{}, 0, 1000)
+		for i := 0; i < 1000; i++ {
+			dogs = append(dogs, map[string]interface{}{"id": strconv.Itoa(i), "name": "Rex"})
+		}
+		body, err := json.Marshal(map[string]interface{}{"data": map[string]interface{}{"result": dogs}})
+		require.NoError(t, err)
+
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Content-Type", "application/json")
+			for i := 0; i < len(body); i += 1000 {
+				end := i + 1000
+				if end > len(body) {
+					end = len(body)
+				}
+				_, _ = writer.Write(body[i:end])
+				writer.(http.Flusher).Flush()
+			}
+		}))
+		defer server.Close()
+
+		logger, entries := newRecordingLogger()
+		client := graphql.NewClient(server.URL, graphql.WithLogger(logger), graphql.WithRedaction(graphql.RedactionPolicy{MaxBodyLength: 100}))
+
+		var response []*schema.Dog
+		err = client.Query(context.Background(), "dogs", nil, &response)
+		require.NoError(t, err)
+		assert.Equal(t, 1000, len(response))
+
+		entry, found := findLogEntry(entries(), "Received response")
+		require.True(t, found)
+		assert.Equal(t, string(body[:100])+"... (truncated)", entry.fields[graphql.LogFieldBody])
+		assert.Equal(t, int64(len(body)), entry.fields[graphql.LogFieldBodySize])
+	})
+}
+
+type logEntry struct {
+	level   graphql.LogLevel
+	message string
+	fields  map[string]interface{}
+}
+
+func newRecordingLogger() (graphql.Logger, func() []logEntry) {
+	var (
+		mutex   sync.Mutex
+		entries []logEntry
+	)
+
+	logger := graphql.LoggerFunc(func(ctx context.Context, level graphql.LogLevel, message string, fields ...graphql.LogField) {
+		entry := logEntry{level: level, message: message, fields: map[string]interface{}{}}
+		for _, field := range fields {
+			entry.fields[field.Key] = field.Value
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		entries = append(entries, entry)
+	})
+
+	return logger, func() []logEntry {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return entries
+	}
+}
+
+func findLogEntry(entries []logEntry, message string) (logEntry, bool) {
+	for _, entry := range entries {
+		if entry.message == message {
+			return entry, true
+		}
+	}
+
+	return logEntry{}, false
+}
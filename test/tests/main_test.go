@@ -2,10 +2,16 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
+	"net/textproto"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -28,9 +34,14 @@ var (
 	config   Config
 	resolver *schema.Resolver
 
-	apiAddress    string
-	errorsAddress string
-	nonGQLAddress string
+	apiAddress         string
+	incrementalAddress string
+	errorsAddress      string
+	nonGQLAddress      string
+)
+
+const (
+	incrementalEndpoint = "/graphql-incremental"
 )
 
 func TestMain(m *testing.M) {
@@ -66,6 +77,7 @@ func setupTests(m *testing.M) int {
 
 	router.HandleFunc("/", handler.Playground("Dataloader", config.Endpoint))
 	router.HandleFunc(config.Endpoint, handler.GraphQL(executableSchema))
+	router.HandleFunc(incrementalEndpoint, serveIncrementalHuman)
 
 	router.HandleFunc("/error/noGQL", func(writer http.ResponseWriter, request *http.Request) {
 		writer.WriteHeader(http.StatusForbidden)
@@ -85,6 +97,7 @@ func setupTests(m *testing.M) int {
 	}()
 
 	apiAddress = fmt.Sprintf("http://%s%s", config.Address, config.Endpoint)
+	incrementalAddress = fmt.Sprintf("http://%s%s", config.Address, incrementalEndpoint)
 	errorsAddress = fmt.Sprintf("http://%s%s", config.Address, "/errors")
 	nonGQLAddress = fmt.Sprintf("http://%s%s", config.Address, "/noGQL")
 
@@ -93,6 +106,96 @@ func setupTests(m *testing.M) int {
 	return m.Run()
 }
 
+// newPersistedQueriesAPI returns server with the test API supporting persisted queries.
+// Each server has its own cache, so that tests do not depend on queries persisted by other tests.
+func newPersistedQueriesAPI() *httptest.Server {
+	executableSchema := schema.NewExecutableSchema(schema.Config{Resolvers: resolver})
+
+	return httptest.NewServer(handler.GraphQL(executableSchema, handler.EnablePersistedQueryCache(newPersistedQueryCache())))
+}
+
 func newResolver() *schema.Resolver {
 	return &schema.Resolver{}
 }
+
+type persistedQueryCache struct {
+	mutex   sync.Mutex
+	queries map[string]string
+}
+
+func newPersistedQueryCache() *persistedQueryCache {
+	return &persistedQueryCache{queries: map[string]string{}}
+}
+
+func (c *persistedQueryCache) Add(ctx context.Context, hash string, query string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.queries[hash] = query
+}
+
+func (c *persistedQueryCache) Get(ctx context.Context, hash string) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	query, ok := c.queries[hash]
+	return query, ok
+}
+
+// serveIncrementalHuman is a stand-in for the server supporting incremental delivery.
+// Regardless of the query, it responds as if the first human was queried with its name deferred
+// and dogs streamed with initial count of 1:
+//
+//	query { human(id: $id) { id ... @defer { name } dogs @stream(initialCount: 1) { id name } } }
+//
+// If the client does not accept multipart/mixed responses the complete result is returned.
+func serveIncrementalHuman(writer http.ResponseWriter, request *http.Request) {
+	human := resolver.HumansDb[0]
+	dogs := make([]map[string]interface{}, 0, len(human.Dogs))
+	for _, dog := range human.Dogs {
+		dogs = append(dogs, map[string]interface{}{"id": dog.ID, "name": dog.Name})
+	}
+
+	if !strings.Contains(request.Header.Get("Accept"), "multipart/mixed") {
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"human": map[string]interface{}{"id": human.ID, "name": human.Name, "dogs": dogs},
+			},
+		})
+		return
+	}
+
+	payloads := []map[string]interface{}{
+		{
+			"data": map[string]interface{}{
+				"human": map[string]interface{}{"id": human.ID, "dogs": dogs[:1]},
+			},
+			"hasNext": true,
+		},
+		{
+			"incremental": []interface{}{
+				map[string]interface{}{"data": map[string]interface{}{"name": human.Name}, "path": []interface{}{"human"}},
+			},
+			"hasNext": true,
+		},
+		{
+			"incremental": []interface{}{
+				map[string]interface{}{"items": dogs[1:], "path": []interface{}{"human", "dogs", 1}},
+			},
+			"hasNext": false,
+		},
+	}
+
+	multipartWriter := multipart.NewWriter(writer)
+	writer.Header().Set("Content-Type", fmt.Sprintf(`multipart/mixed; boundary="%s"; deferSpec=20220824`, multipartWriter.Boundary()))
+	writer.WriteHeader(http.StatusOK)
+
+	for _, payload := range payloads {
+		part, err := multipartWriter.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json; charset=utf-8"}})
+		if err != nil {
+			return
+		}
+		_ = json.NewEncoder(part).Encode(payload)
+		writer.(http.Flusher).Flush()
+	}
+	_ = multipartWriter.Close()
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_Metrics(t *testing.T) {
+	defer resolver.ResetData()
+
+	t.Run("should report metrics of executed operation", func(t *testing.T) {
+		var observed []graphql.OperationMetrics
+		client := graphql.NewClient(apiAddress, graphql.WithMetrics(graphql.MetricsFunc(func(ctx context.Context, metrics graphql.OperationMetrics) {
+			observed = append(observed, metrics)
+		})))
+
+		var response struct {
+			Result []*schema.Dog `json:"result"`
+		}
+		err := client.Execute(context.Background(), graphql.NewRequestRaw("query Dogs { result: dogs { id } }"), &response)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, len(observed))
+		metrics := observed[0]
+		assert.Equal(t, graphql.Query, metrics.OperationType)
+		assert.Equal(t, "Dogs", metrics.OperationName)
+		assert.Equal(t, http.StatusOK, metrics.StatusCode)
+		assert.True(t, metrics.Duration > 0)
+		assert.Equal(t, 0, metrics.Retries)
+		assert.True(t, metrics.RequestSize > 0)
+		assert.True(t, metrics.ResponseSize > 0)
+		assert.Empty(t, metrics.Errors)
+		assert.NoError(t, metrics.Err)
+	})
+
+	t.Run("should report GraphQL errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Content-Type", "application/json")
+			_, _ = writer.Write([]byte(`{"errors":[{"message":"forbidden","extensions":{"code":"FORBIDDEN"}},{"message":"failed"}],"data":null}`))
+		}))
+		defer server.Close()
+
+		var observed []graphql.OperationMetrics
+		client := graphql.NewClient(server.URL, graphql.WithMetrics(graphql.MetricsFunc(func(ctx context.Context, metrics graphql.OperationMetrics) {
+			observed = append(observed, metrics)
+		})))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+
+		require.Equal(t, 1, len(observed))
+		metrics := observed[0]
+		assert.Equal(t, http.StatusOK, metrics.StatusCode)
+		require.Equal(t, 2, len(metrics.Errors))
+		assert.Equal(t, "FORBIDDEN", metrics.Errors[0].Code())
+		assert.Equal(t, "", metrics.Errors[1].Code())
+		assert.Equal(t, err, metrics.Err)
+	})
+
+	t.Run("should not report sizes of operations sent in batch", func(t *testing.T) {
+		server, batchSizes := newBatchingServer(t)
+		defer server.Close()
+
+		var (
+			mutex    sync.Mutex
+			observed []graphql.OperationMetrics
+		)
+		client := graphql.NewClient(server.URL,
+			graphql.WithBatching(time.Second, 3),
+			graphql.WithMetrics(graphql.MetricsFunc(func(ctx context.Context, metrics graphql.OperationMetrics) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				observed = append(observed, metrics)
+			})),
+		)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var dogs []*schema.Dog
+				assert.NoError(t, client.Query(context.Background(), "dogs", nil, &dogs))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, []int{3}, batchSizes())
+		require.Equal(t, 3, len(observed))
+		for _, metrics := range observed {
+			assert.Equal(t, http.StatusOK, metrics.StatusCode)
+			assert.Equal(t, int64(0), metrics.RequestSize)
+			assert.Equal(t, int64(0), metrics.ResponseSize)
+		}
+	})
+
+	t.Run("should report retries and status of the last response", func(t *testing.T) {
+		server, _ := newFlakyServer(t, 2, http.StatusBadGateway)
+		defer server.Close()
+
+		var observed []graphql.OperationMetrics
+		client := graphql.NewClient(server.URL,
+			graphql.WithRetry(graphql.RetryPolicy{
+				MaxAttempts:          2,
+				InitialBackoff:       time.Millisecond,
+				RetryableStatusCodes: []int{http.StatusBadGateway},
+			}),
+			graphql.WithMetrics(graphql.MetricsFunc(func(ctx context.Context, metrics graphql.OperationMetrics) {
+				observed = append(observed, metrics)
+			})),
+		)
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+
+		require.Equal(t, 1, len(observed))
+		assert.Equal(t, 1, observed[0].Retries)
+		assert.Equal(t, http.StatusBadGateway, observed[0].StatusCode)
+	})
+}
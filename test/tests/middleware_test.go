@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_Middleware(t *testing.T) {
+	defer resolver.ResetData()
+
+	dogID := uuid.New().String()
+	resolver.DogsDb = []*schema.Dog{{ID: dogID, Name: "Rex"}}
+
+	t.Run("should call middlewares in order", func(t *testing.T) {
+		var calls []string
+
+		recordingMiddleware := func(name string) graphql.Middleware {
+			return func(next graphql.Handler) graphql.Handler {
+				return func(ctx context.Context, request graphql.Request, responseOut interface{}) (*graphql.Response, error) {
+					calls = append(calls, "before "+name)
+					response, err := next(ctx, request, responseOut)
+					calls = append(calls, "after "+name)
+					return response, err
+				}
+			}
+		}
+
+		client := graphql.NewClient(apiAddress, graphql.WithMiddleware(recordingMiddleware("first"), recordingMiddleware("second")))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"before first", "before second", "after second", "after first"}, calls)
+	})
+
+	t.Run("should allow to modify request and inspect response", func(t *testing.T) {
+		var (
+			request    graphql.Request
+			response   *graphql.Response
+			decodedDog schema.Dog
+		)
+
+		middleware := func(next graphql.Handler) graphql.Handler {
+			return func(ctx context.Context, req graphql.Request, responseOut interface{}) (*graphql.Response, error) {
+				req.Header.Set("Middleware", "value")
+				request = req
+
+				res, err := next(ctx, req, responseOut)
+				response = res
+				decodedDog = responseOut.(*struct {
+					Result schema.Dog `json:"result"`
+				}).Result
+				return res, err
+			}
+		}
+
+		client := graphql.NewClient(apiAddress, graphql.WithMiddleware(middleware))
+
+		var dog struct {
+			Result schema.Dog `json:"result"`
+		}
+		err := client.Execute(context.Background(), graphql.Request{
+			Query:         "query DogQuery($id: ID!) { result: dog(id: $id) { id name } }",
+			OperationName: "DogQuery",
+			Variables:     map[string]interface{}{"id": dogID},
+			Header:        http.Header{},
+		}, &dog)
+		require.NoError(t, err)
+
+		assert.Equal(t, "DogQuery", request.OperationName)
+		assert.Equal(t, dogID, request.Variables["id"])
+		assert.Equal(t, "value", request.Header.Get("Middleware"))
+		require.NotNil(t, response)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "Rex", decodedDog.Name)
+		assert.Equal(t, "Rex", dog.Result.Name)
+	})
+
+	t.Run("should return error from middleware", func(t *testing.T) {
+		middleware := func(next graphql.Handler) graphql.Handler {
+			return func(ctx context.Context, request graphql.Request, responseOut interface{}) (*graphql.Response, error) {
+				return nil, assert.AnError
+			}
+		}
+
+		client := graphql.NewClient(apiAddress, graphql.WithMiddleware(middleware))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+		assert.Equal(t, assert.AnError, err)
+	})
+}
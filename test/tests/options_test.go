@@ -14,15 +14,30 @@ import (
 )
 
 func Test_Options(t *testing.T) {
-	noTimeoutHttpClient := &http.Client{
-		Timeout: 1 * time.Nanosecond,
-	}
+	t.Run("should execute compact queries", func(t *testing.T) {
+		defer resolver.ResetData()
 
-	client := graphql.NewClient(apiAddress, graphql.WithHTTPClient(noTimeoutHttpClient))
+		client := graphql.NewClient(apiAddress, graphql.WithParserOptions(graphql.ParserOptions{Compact: true}))
 
-	var dogs []*schema.Dog
-	err := client.Query(context.Background(), "dogs", nil, &dogs)
-	require.Error(t, err)
+		var human schema.Human
+		err := client.Mutate(context.Background(), "createHuman", graphql.OperationInput{"in": humanInput("Ted", []*schema.DogInput{dogInput("Rex", nil, nil)})}, &human)
+		require.NoError(t, err)
+		assert.Equal(t, "Ted", human.Name)
+		require.Equal(t, 1, len(human.Dogs))
+		assert.Equal(t, "Rex", human.Dogs[0].Name)
+	})
 
-	assert.Contains(t, err.Error(), "Client.Timeout")
+	t.Run("should use provided http client", func(t *testing.T) {
+		noTimeoutHttpClient := &http.Client{
+			Timeout: 1 * time.Nanosecond,
+		}
+
+		client := graphql.NewClient(apiAddress, graphql.WithHTTPClient(noTimeoutHttpClient))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+
+		assert.Contains(t, err.Error(), "Client.Timeout")
+	})
 }
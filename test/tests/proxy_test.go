@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// proxyHook inspects the request received by the recording proxy and returns the value recorded for it.
+// If the hook returns the handler, the request is served by it instead of being forwarded to the test API.
+type proxyHook[T any] func(request *http.Request) (T, proxyHandler)
+
+// proxyHandler serves the request instead of the recording proxy, it can forward the request with the proxy
+type proxyHandler func(writer http.ResponseWriter, request *http.Request, proxy http.Handler)
+
+// newRecordingProxy returns server forwarding requests to the test API, recording values returned by the hook,
+// and the function returning values recorded for all requests received so far
+func newRecordingProxy[T any](t *testing.T, hook proxyHook[T]) (*httptest.Server, func() []T) {
+	return newRecordingProxyTo(t, apiAddress, hook)
+}
+
+// newRecordingProxyTo works like newRecordingProxy, proxying requests to the address
+func newRecordingProxyTo[T any](t *testing.T, address string, hook proxyHook[T]) (*httptest.Server, func() []T) {
+	var (
+		mutex   sync.Mutex
+		records []T
+	)
+	proxy := newProxy(t, address)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		record, handler := hook(request)
+
+		// Value is recorded before the response is sent, so that it is available when the client receives it
+		mutex.Lock()
+		records = append(records, record)
+		mutex.Unlock()
+
+		if handler == nil {
+			proxy.ServeHTTP(writer, request)
+			return
+		}
+		handler(writer, request, proxy)
+	}))
+
+	return server, func() []T {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]T(nil), records...)
+	}
+}
+
+func newAPIProxy(t *testing.T) http.Handler {
+	return newProxy(t, apiAddress)
+}
+
+// newProxy returns handler forwarding requests to the address, regardless of their path
+func newProxy(t *testing.T, address string) http.Handler {
+	targetURL, err := url.Parse(address)
+	require.NoError(t, err)
+
+	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+	director := proxy.Director
+	proxy.Director = func(request *http.Request) {
+		director(request)
+		request.URL.Path = targetURL.Path
+	}
+
+	return proxy
+}
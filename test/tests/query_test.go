@@ -49,4 +49,36 @@ func Test_Query(t *testing.T) {
 		assert.Equal(t, "test", dog.Name)
 	})
 
+	t.Run("query dogs with type name", func(t *testing.T) {
+		client := graphql.NewClient(apiAddress, graphql.WithParserOptions(graphql.ParserOptions{IncludeTypeName: true}))
+
+		var dogs []struct {
+			graphql.TypeName
+			schema.Dog
+		}
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, len(dogs))
+		assert.Equal(t, "Dog", dogs[0].GetTypeName())
+		assert.Equal(t, dogID, dogs[0].ID)
+	})
+
+	t.Run("query dog with embedded struct", func(t *testing.T) {
+		input := graphql.OperationInput{
+			"id": dogID,
+		}
+
+		var dog struct {
+			schema.Dog
+			Name string `json:"name"`
+		}
+		err := gqlClient.Query(context.Background(), "dog", input, &dog)
+		require.NoError(t, err)
+
+		assert.Equal(t, dogID, dog.ID)
+		assert.Equal(t, "test", dog.Name)
+		assert.Empty(t, dog.Dog.Name)
+	})
+
 }
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_RateLimit(t *testing.T) {
+	defer resolver.ResetData()
+
+	t.Run("should limit rate of requests", func(t *testing.T) {
+		client := graphql.NewClient(apiAddress, graphql.WithRateLimit(20, 1))
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			var dogs []*schema.Dog
+			err := client.Query(context.Background(), "dogs", nil, &dogs)
+			require.NoError(t, err)
+		}
+
+		assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	})
+
+	t.Run("should limit rate of HTTP requests sent for the operation", func(t *testing.T) {
+		api := newPersistedQueriesAPI()
+		defer api.Close()
+
+		client := graphql.NewClient(api.URL, graphql.WithAutomaticPersistedQueries(), graphql.WithRateLimit(10, 1))
+
+		start := time.Now()
+		// Query is sent with its hash and then registered, which makes two HTTP requests
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+
+		assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	})
+
+	t.Run("should stop waiting when context is done", func(t *testing.T) {
+		client := graphql.NewClient(apiAddress, graphql.WithRateLimit(0.1, 1))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err = client.Query(ctx, "dogs", nil, &dogs)
+		require.Error(t, err)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.True(t, time.Since(start) < time.Second)
+	})
+
+	t.Run("should limit concurrency of requests", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+		proxy := newAPIProxy(t)
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				observed := atomic.LoadInt32(&maxInFlight)
+				if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+					break
+				}
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			proxy.ServeHTTP(writer, request)
+		}))
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithMaxConcurrency(2))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var dogs []*schema.Dog
+				assert.NoError(t, client.Query(context.Background(), "dogs", nil, &dogs))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight))
+	})
+
+	t.Run("should pause requests when server reports exceeded rate limit", func(t *testing.T) {
+		var requests int32
+		var requestTimes []time.Time
+		proxy := newAPIProxy(t)
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			requestTimes = append(requestTimes, time.Now())
+			if atomic.AddInt32(&requests, 1) == 1 {
+				writer.Header().Set("Content-Type", "application/json")
+				_, _ = writer.Write([]byte(`{"errors":[{"message":"slow down","extensions":{"code":"RATE_LIMITED","retryAfter":0.2}}],"data":null}`))
+				return
+			}
+			proxy.ServeHTTP(writer, request)
+		}))
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithMaxConcurrency(1))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+
+		err = client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+
+		require.Equal(t, 2, len(requestTimes))
+		assert.True(t, requestTimes[1].Sub(requestTimes[0]) >= 200*time.Millisecond)
+	})
+
+	t.Run("should wait for Retry-After before retrying", func(t *testing.T) {
+		var requestTimes []time.Time
+		proxy := newAPIProxy(t)
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			requestTimes = append(requestTimes, time.Now())
+			if len(requestTimes) == 1 {
+				writer.Header().Set(graphql.RetryAfterHeader, "1")
+				writer.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			proxy.ServeHTTP(writer, request)
+		}))
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithRetry(graphql.RetryPolicy{
+			MaxAttempts:          2,
+			InitialBackoff:       time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusTooManyRequests},
+		}))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+
+		require.Equal(t, 2, len(requestTimes))
+		assert.True(t, requestTimes[1].Sub(requestTimes[0]) >= time.Second)
+	})
+}
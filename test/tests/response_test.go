@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_ExecuteWithResponse(t *testing.T) {
+	defer resolver.ResetData()
+
+	t.Run("should return response with extensions and headers", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Content-Type", graphql.MediaTypeGraphQLResponse)
+			writer.Header().Set("X-RateLimit-Remaining", "99")
+			_, _ = writer.Write([]byte(`{"data":{"result":[{"id":"1","name":"Rex"}]},"extensions":{"cost":{"requestedQueryCost":3}}}`))
+		}))
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL)
+
+		var data struct {
+			Result []*schema.Dog `json:"result"`
+		}
+		response, err := client.ExecuteWithResponse(context.Background(), graphql.NewRequestRaw("query { result: dogs { id name } }"), &data)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, len(data.Result))
+		assert.Equal(t, "Rex", data.Result[0].Name)
+
+		require.NotNil(t, response)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, graphql.MediaTypeGraphQLResponse, response.MediaType)
+		assert.Equal(t, "99", response.Header.Get("X-RateLimit-Remaining"))
+		assert.Equal(t, map[string]interface{}{"cost": map[string]interface{}{"requestedQueryCost": float64(3)}}, response.Extensions)
+		assert.Empty(t, response.Errors)
+		assert.True(t, response.Duration > 0)
+	})
+
+	t.Run("should return response for operations", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Content-Type", graphql.MediaTypeGraphQLResponse)
+			writer.Header().Set("X-RateLimit-Remaining", "99")
+			_, _ = writer.Write([]byte(`{"data":{"result":[{"id":"1","name":"Rex"}]},"extensions":{"cost":3}}`))
+		}))
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL)
+
+		var dogs []*schema.Dog
+		response, err := client.QueryWithResponse(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(dogs))
+		assert.Equal(t, "Rex", dogs[0].Name)
+		require.NotNil(t, response)
+		assert.Equal(t, "99", response.Header.Get("X-RateLimit-Remaining"))
+		assert.Equal(t, float64(3), response.Extensions["cost"])
+
+		result, err := graphql.QueryAsWithResponse[[]schema.Dog](context.Background(), client, "dogs", nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Data))
+		assert.Equal(t, "Rex", result.Data[0].Name)
+		require.NotNil(t, result.Response)
+		assert.Equal(t, http.StatusOK, result.Response.StatusCode)
+		assert.Equal(t, float64(3), result.Response.Extensions["cost"])
+	})
+
+	t.Run("should return response with GraphQL errors of typed operation", func(t *testing.T) {
+		client := graphql.NewClient(apiAddress)
+
+		result, err := graphql.QueryAsWithResponse[string](context.Background(), client, "errorsQuery", nil)
+		require.Error(t, err)
+
+		require.NotNil(t, result.Response)
+		require.Equal(t, 1, len(result.Response.Errors))
+		assert.Contains(t, result.Response.Errors[0].Message, "error you requested")
+	})
+
+	t.Run("should return response with GraphQL errors", func(t *testing.T) {
+		client := graphql.NewClient(apiAddress)
+
+		var data interface{}
+		response, err := client.ExecuteWithResponse(context.Background(), graphql.NewRequestRaw("query { result: errorsQuery }"), &data)
+		require.Error(t, err)
+
+		require.NotNil(t, response)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, 1, len(response.Errors))
+		assert.Contains(t, response.Errors[0].Message, "error you requested")
+	})
+
+	t.Run("should return response with headers when status is unexpected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Content-Type", graphql.MediaTypeGraphQLResponse)
+			writer.Header().Set(graphql.RetryAfterHeader, "10")
+			writer.WriteHeader(http.StatusTooManyRequests)
+			_, _ = writer.Write([]byte(`{"errors":[{"message":"rate limited"}],"extensions":{"cost":{"throttled":true}}}`))
+		}))
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL)
+
+		var data interface{}
+		response, err := client.ExecuteWithResponse(context.Background(), graphql.NewRequestRaw("query { result: dogs { id } }"), &data)
+		require.Error(t, err)
+
+		var httpErr *graphql.HTTPError
+		require.True(t, errors.As(err, &httpErr))
+
+		require.NotNil(t, response)
+		assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+		assert.Equal(t, "10", response.Header.Get(graphql.RetryAfterHeader))
+		require.Equal(t, 1, len(response.Errors))
+		assert.Equal(t, map[string]interface{}{"cost": map[string]interface{}{"throttled": true}}, response.Extensions)
+	})
+
+	t.Run("should return separate responses for operations in batch", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Content-Type", "application/json")
+			_, _ = writer.Write([]byte(`[{"data":{"result":"first"},"extensions":{"index":0}},{"data":null,"errors":[{"message":"failed"}],"extensions":{"index":1}}]`))
+		}))
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithBatching(100*time.Millisecond, 2))
+
+		responses := make([]*graphql.Response, 2)
+		queries := []string{"query { result: first }", "query { result: second }"}
+
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		for i := range queries {
+			wg.Add(1)
+			go func(query string) {
+				defer wg.Done()
+				var data interface{}
+				response, _ := client.ExecuteWithResponse(context.Background(), graphql.NewRequestRaw(query), &data)
+				mutex.Lock()
+				defer mutex.Unlock()
+				if len(response.Errors) > 0 {
+					responses[1] = response
+				} else {
+					responses[0] = response
+				}
+			}(queries[i])
+		}
+		wg.Wait()
+
+		require.NotNil(t, responses[0])
+		require.NotNil(t, responses[1])
+		assert.Equal(t, float64(0), responses[0].Extensions["index"])
+		assert.Equal(t, float64(1), responses[1].Extensions["index"])
+		assert.Equal(t, "failed", responses[1].Errors[0].Message)
+	})
+}
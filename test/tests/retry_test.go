@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_Retry(t *testing.T) {
+	defer resolver.ResetData()
+
+	retryPolicy := graphql.RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       10 * time.Millisecond,
+		Multiplier:           2,
+		RetryableStatusCodes: []int{http.StatusBadGateway},
+		RetryableErrorCodes:  []string{"UNAVAILABLE"},
+	}
+
+	t.Run("should retry query until it succeeds", func(t *testing.T) {
+		server, requests := newFlakyServer(t, 2, http.StatusBadGateway)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithRetry(retryPolicy))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+	})
+
+	t.Run("should return last error when attempts are exhausted", func(t *testing.T) {
+		server, requests := newFlakyServer(t, 5, http.StatusBadGateway)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithRetry(retryPolicy))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+
+		var httpErr *graphql.HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+	})
+
+	t.Run("should not retry on non retryable status", func(t *testing.T) {
+		server, requests := newFlakyServer(t, 1, http.StatusInternalServerError)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithRetry(retryPolicy))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.Error(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+	})
+
+	t.Run("should retry on retryable GraphQL error code", func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if atomic.AddInt32(&requests, 1) == 1 {
+				writer.Header().Set("Content-Type", "application/json")
+				_, _ = writer.Write([]byte(`{"errors":[{"message":"try later","extensions":{"code":"UNAVAILABLE"}}],"data":null}`))
+				return
+			}
+			newAPIProxy(t).ServeHTTP(writer, request)
+		}))
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithRetry(retryPolicy))
+
+		var dogs []*schema.Dog
+		err := client.Query(context.Background(), "dogs", nil, &dogs)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("should retry mutation only if marked as idempotent", func(t *testing.T) {
+		server, requests := newFlakyServer(t, 1, http.StatusBadGateway)
+		defer server.Close()
+
+		client := graphql.NewClient(server.URL, graphql.WithRetry(retryPolicy))
+
+		operation := graphql.Operation{
+			Type:  graphql.Mutation,
+			Name:  "createHuman",
+			Input: graphql.OperationInput{"in": humanInput("Ted", nil)},
+		}
+
+		var human schema.Human
+		err := client.Run(context.Background(), operation, &human)
+		require.Error(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+
+		atomic.StoreInt32(requests, 0)
+		operation.Idempotent = true
+
+		err = client.Run(context.Background(), operation, &human)
+		require.NoError(t, err)
+		assert.Equal(t, "Ted", human.Name)
+	})
+
+	t.Run("should stop retrying when context is canceled", func(t *testing.T) {
+		server, requests := newFlakyServer(t, 5, http.StatusBadGateway)
+		defer server.Close()
+
+		policy := retryPolicy
+		policy.InitialBackoff = time.Minute
+
+		client := graphql.NewClient(server.URL, graphql.WithRetry(policy))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		var dogs []*schema.Dog
+		err := client.Query(ctx, "dogs", nil, &dogs)
+		require.Error(t, err)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+	})
+}
+
+// newFlakyServer returns server responding with the status for the number of first requests
+// and then proxying requests to the test API
+func newFlakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *int32) {
+	var requests int32
+
+	server, _ := newRecordingProxy(t, func(request *http.Request) (int32, proxyHandler) {
+		attempt := atomic.AddInt32(&requests, 1)
+		if attempt > failures {
+			return attempt, nil
+		}
+
+		return attempt, func(writer http.ResponseWriter, request *http.Request, proxy http.Handler) {
+			writer.WriteHeader(status)
+		}
+	})
+
+	return server, &requests
+}
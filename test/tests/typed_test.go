@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymongib/graphql-client/test/schema"
+
+	"github.com/szymongib/graphql-client/graphql"
+)
+
+func Test_TypedOperations(t *testing.T) {
+	defer resolver.ResetData()
+
+	gqlClient := graphql.NewClient(apiAddress)
+
+	human, err := graphql.MutateAs[schema.Human](context.Background(), gqlClient, "createHuman", graphql.OperationInput{"in": humanInput("Ted", nil)})
+	require.NoError(t, err)
+	assert.NotEmpty(t, human.ID)
+	assert.Equal(t, "Ted", human.Name)
+
+	dog, err := graphql.MutateAs[*schema.Dog](context.Background(), gqlClient, "createDog", graphql.OperationInput{"humanID": human.ID, "in": dogInput("Rex", nil, nil)})
+	require.NoError(t, err)
+	require.NotNil(t, dog)
+	assert.Equal(t, "Rex", dog.Name)
+	assert.Equal(t, human.ID, dog.OwnerID)
+
+	queriedHuman, err := graphql.QueryAs[schema.Human](context.Background(), gqlClient, "human", graphql.OperationInput{"id": human.ID})
+	require.NoError(t, err)
+	assert.Equal(t, human.ID, queriedHuman.ID)
+	assert.Equal(t, "Ted", queriedHuman.Name)
+
+	dogs, err := graphql.QueryAs[[]schema.Dog](context.Background(), gqlClient, "dogs", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(dogs))
+	assert.Equal(t, dog.ID, dogs[0].ID)
+
+	type dogName struct {
+		Name string `json:"name"`
+	}
+	names, err := graphql.RunAs[[]dogName](context.Background(), gqlClient, graphql.Operation{
+		Type:      graphql.Query,
+		Name:      "dogs",
+		Requested: []dogName{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []dogName{{Name: "Rex"}}, names)
+
+	_, err = graphql.QueryAs[string](context.Background(), gqlClient, "errorsQuery", nil)
+	require.Error(t, err)
+}
@@ -31,6 +31,16 @@ if [[ $? != 0 ]]; then
 else echo -e "${GREEN}√ go test${NC}"
 fi
 
+for module in graphql/tracing graphql/metrics; do
+	echo "? go test ${module}"
+	(cd "${DIR}/${module}" && go mod tidy -diff && go test ./...)
+	if [[ $? != 0 ]]; then
+		echo -e "${RED}✗ go test ${module}\n${NC}"
+		exit 1
+	else echo -e "${GREEN}√ go test ${module}${NC}"
+	fi
+done
+
 goFilesToCheck=$(find . -type f -name "*.go" | egrep -v "\/vendor\/|_*/automock/|_*/testdata/|_*export_test.go")
 
 goFmtResult=$(echo "${goFilesToCheck}" | xargs -L1 go fmt)